@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/glestaris/issuez"
+	"github.com/glestaris/issuez/markdown"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportJQL        string
+	exportOutputPath string
+	exportSeparator  string
+)
+
+func init() {
+	exportCmd.PersistentFlags().StringVarP(
+		&exportJQL, "jql", "q", "",
+		"JQL query, or GitHub search query, selecting the issues to export",
+	)
+	exportCmd.MarkPersistentFlagRequired("jql")
+	exportCmd.PersistentFlags().StringVarP(
+		&exportOutputPath, "output", "o", "",
+		"Path of the markdown file to write (defaults to stdout)",
+	)
+	exportCmd.PersistentFlags().StringVar(
+		&exportSeparator, "separator", markdown.LegacySeparator,
+		"Line separating the issues (needed for descriptions with"+
+			" horizontal rules)",
+	)
+	rootCmd.AddCommand(exportCmd)
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports issues of the tracker as a markdown file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		trackerService, err := newTrackerService(cmd, nil)
+		if err != nil {
+			fmt.Fprintf(
+				os.Stderr, "Failed to initalise tracker service: %s\n", err,
+			)
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext()
+		defer cancel()
+		issues, err := trackerService.ExportIssues(ctx, exportJQL)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to export issues: %s\n", err)
+			os.Exit(1)
+		}
+
+		out := os.Stdout
+		if exportOutputPath != "" {
+			out, err = os.Create(exportOutputPath)
+			if err != nil {
+				fmt.Fprintf(
+					os.Stderr, "Failed to create markdown file '%s': %s\n",
+					exportOutputPath, err,
+				)
+				os.Exit(1)
+			}
+			defer out.Close()
+		}
+
+		if err := issuez.WriteExportFile(out, issues, exportSeparator); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d issues\n", len(issues))
+	},
+}
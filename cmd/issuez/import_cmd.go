@@ -0,0 +1,325 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/glestaris/issuez"
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/markdown"
+)
+
+var (
+	jiraProjectKey          string
+	importRecursive         bool
+	importFailFast          bool
+	importMaxAttachmentSize int64
+	importLinkIssueKeys     bool
+	importSeparator         string
+	importSections          string
+	importHeadingLevel      int
+	importProlific          bool
+	importReleaseType       string
+	importReleaseVersions   bool
+	importFormat            string
+	importColumns           []string
+	importTemplate          bool
+	importVars              []string
+	importTypeAliases       []string
+)
+
+func init() {
+	importCmd.PersistentFlags().StringVarP(
+		&jiraProjectKey, "project-key", "p", "", "JIRA project key",
+	)
+	importCmd.PersistentFlags().BoolVarP(
+		&importRecursive, "recursive", "r", false,
+		"Import markdown files found in directories recursively",
+	)
+	importCmd.PersistentFlags().BoolVar(
+		&importFailFast, "fail-fast", false,
+		"Stop without importing anything if a markdown file fails to parse"+
+			" (otherwise the issues of the other files are imported and"+
+			" the command exits with 1)",
+	)
+	importCmd.PersistentFlags().Int64Var(
+		&importMaxAttachmentSize, "max-attachment-size", 10,
+		"Maximum size of uploaded attachments in MB (0 for no limit)",
+	)
+	importCmd.PersistentFlags().BoolVar(
+		&importLinkIssueKeys, "link-issue-keys", false,
+		"Turn the issue keys of the project in descriptions into links",
+	)
+	importCmd.PersistentFlags().StringVar(
+		&importSeparator, "separator", markdown.LegacySeparator,
+		"Line separating the issues (horizontal rules are kept in"+
+			" descriptions unless it is '"+markdown.LegacySeparator+"')",
+	)
+	importCmd.PersistentFlags().StringVar(
+		&importSections, "sections", "separator",
+		"How issues are found: 'separator' or 'headings'",
+	)
+	importCmd.PersistentFlags().IntVar(
+		&importHeadingLevel, "heading-level",
+		markdown.DefaultIssueHeadingLevel,
+		"Level of the headings issues start at with --sections=headings",
+	)
+	importCmd.PersistentFlags().BoolVar(
+		&importProlific, "prolific", false,
+		"Separate issues at every '---' line, even right after a line of"+
+			" text, as Prolific does",
+	)
+	importCmd.PersistentFlags().StringVar(
+		&importReleaseType, "release-type", "Task",
+		"JIRA issue type of the [Release] issues",
+	)
+	importCmd.PersistentFlags().BoolVar(
+		&importReleaseVersions, "release-versions", false,
+		"Import [Release] issues as versions the issues before them are"+
+			" fixed in",
+	)
+	importCmd.PersistentFlags().StringVar(
+		&importFormat, "format", "",
+		"Format of the files: markdown, csv or yaml (defaults to the"+
+			" format of their extension)",
+	)
+	importCmd.PersistentFlags().StringArrayVar(
+		&importColumns, "column", []string{},
+		"Map a CSV column to a field, as <header>=<field> (fields are"+
+			" summary, type, description, epic, labels and attachments)",
+	)
+	importCmd.PersistentFlags().BoolVar(
+		&importTemplate, "template", false,
+		"Render the files as Go templates before parsing them (implied by"+
+			" --var)",
+	)
+	importCmd.PersistentFlags().StringArrayVar(
+		&importVars, "var", []string{},
+		"Set a template variable, as <name>=<value>",
+	)
+	importCmd.PersistentFlags().StringArrayVar(
+		&importTypeAliases, "type-alias", []string{},
+		"Read an issue type name as another type, as <name>=<type>",
+	)
+	rootCmd.AddCommand(importCmd)
+}
+
+// parseColumns parses the <header>=<field> mappings of CSV columns.
+func parseColumns(mappings []string) (map[string]string, error) {
+	columns := map[string]string{}
+	for _, mapping := range mappings {
+		i := strings.LastIndex(mapping, "=")
+		if i == -1 {
+			return nil, fmt.Errorf("Invalid column mapping '%s'", mapping)
+		}
+		columns[mapping[:i]] = mapping[i+1:]
+	}
+	return columns, nil
+}
+
+// parseVars parses the <name>=<value> template variables.
+func parseVars(assignments []string) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, assignment := range assignments {
+		i := strings.Index(assignment, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("Invalid variable '%s'", assignment)
+		}
+		vars[assignment[:i]] = assignment[i+1:]
+	}
+	return vars, nil
+}
+
+// parseTypeAliases parses the <name>=<type> aliases of issue types.
+func parseTypeAliases(
+	aliases []string,
+) (map[string]domain.IssueType, error) {
+	typeAliases := map[string]domain.IssueType{}
+	for _, alias := range aliases {
+		i := strings.Index(alias, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("Invalid type alias '%s'", alias)
+		}
+		issueType, err := markdown.ParseIssueType(alias[i+1:], nil)
+		if err != nil {
+			return nil, err
+		}
+		typeAliases[alias[:i]] = issueType
+	}
+	return typeAliases, nil
+}
+
+type importFile struct {
+	path   string
+	issues []*domain.Issue
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <Path to Markdown file or - for stdin>...",
+	Short: "Imports markdown files as issues of the tracker",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		markdownFilePaths, err := issuez.FindImportFiles(
+			args, importRecursive, importFormat,
+		)
+		if err != nil {
+			fmt.Printf("Failed to find markdown files: %s\n", err)
+			os.Exit(1)
+		}
+
+		sections, err := markdown.ParseSectionMode(importSections)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		columns, err := parseColumns(importColumns)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		var vars map[string]string
+		if importTemplate || len(importVars) != 0 {
+			vars, err = parseVars(importVars)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+		}
+		typeAliases, err := parseTypeAliases(importTypeAliases)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		options := issuez.ImportFileOptions{
+			Format: importFormat,
+			Markdown: markdown.ParserOptions{
+				Separator:    importSeparator,
+				Sections:     sections,
+				HeadingLevel: importHeadingLevel,
+				Prolific:     importProlific,
+				TypeAliases:  typeAliases,
+			},
+			Columns: columns,
+			Vars:    vars,
+		}
+
+		trackerService, err := newTrackerService(cmd, map[string]flagSetting{
+			"project-key": {"projectKey", jiraProjectKey},
+			"max-attachment-size": {
+				"maxAttachmentSize",
+				strconv.FormatInt(importMaxAttachmentSize*1024*1024, 10),
+			},
+			"link-issue-keys": {
+				"linkIssueKeys", strconv.FormatBool(importLinkIssueKeys),
+			},
+			"release-type": {"releaseIssueType", importReleaseType},
+			"release-versions": {
+				"releaseVersions", strconv.FormatBool(importReleaseVersions),
+			},
+		})
+		if err != nil {
+			fmt.Printf("Failed to initalise tracker service: %s\n", err)
+			os.Exit(1)
+		}
+		importer := issuez.NewImporter(trackerService, options)
+
+		// on Ctrl-C, the requests in flight are cancelled and the issues
+		// which were created before are reported
+		ctx, cancel := commandContext()
+		exitCode := runImport(
+			ctx, os.Stdout, importer, markdownFilePaths, importFailFast,
+		)
+		cancel()
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	},
+}
+
+// runImport parses the files and imports their issues, reporting the issues
+// found in each file, and then the ones imported from it, to out. A file
+// which fails to parse is reported and the issues of the other files are
+// imported, unless failFast is set, which stops at the first such file
+// without importing anything. The issues which fail to import are listed as
+// FAILED, unless the import fails as a whole. It returns the exit code of the
+// command, which is 1 if a file fails to parse or the import fails.
+func runImport(
+	ctx context.Context, out io.Writer, importer *issuez.Importer,
+	paths []string, failFast bool,
+) int {
+	importFiles := []importFile{}
+	issues := []*domain.Issue{}
+	parseFailed := false
+	for _, path := range paths {
+		fileIssues, err := importer.ParseFile(path)
+		if err != nil {
+			fmt.Fprintf(
+				out, "Failed to parse markdown file '%s': %s\n",
+				issuez.ImportFileName(path), err,
+			)
+			if failFast {
+				return 1
+			}
+			parseFailed = true
+			continue
+		}
+		fmt.Fprintf(
+			out, "Found %d issues in the markdown file '%s'\n",
+			len(fileIssues), issuez.ImportFileName(path),
+		)
+
+		importFiles = append(importFiles, importFile{
+			path:   path,
+			issues: fileIssues,
+		})
+		issues = append(issues, fileIssues...)
+	}
+	if len(issues) == 0 {
+		fmt.Fprintln(out, "No issues were found")
+		if parseFailed {
+			return 1
+		}
+		return 0
+	}
+
+	importErr := importer.Import(ctx, issues)
+	if importErr != nil {
+		fmt.Fprintf(out, "Failed to import issues: %s\n", importErr)
+	}
+	for _, f := range importFiles {
+		imported := 0
+		for _, issue := range f.issues {
+			if issue.ID != "" {
+				imported++
+			}
+		}
+		if len(f.issues) == 0 || (importErr != nil && imported == 0) {
+			continue
+		}
+		fmt.Fprintf(
+			out, "Imported issues from '%s':\n", issuez.ImportFileName(f.path),
+		)
+		for _, issue := range f.issues {
+			// - Task (TEST-124): Subject
+			if issue.ID != "" {
+				fmt.Fprintf(
+					out, "- %s (%s): %s\n", issue.Type, issue.ID, issue.Title,
+				)
+			} else if importErr == nil {
+				fmt.Fprintf(
+					out, "- %s (FAILED): %s\n", issue.Type, issue.Title,
+				)
+			}
+		}
+	}
+
+	if importErr != nil || parseFailed {
+		return 1
+	}
+	return 0
+}
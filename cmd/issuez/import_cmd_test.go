@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/glestaris/issuez"
+	"github.com/glestaris/issuez/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTrackerService imports every issue but the ones titled "Rejected", and
+// fails after the first issue if it has an error.
+type fakeTrackerService struct {
+	importedIssues []*domain.Issue
+	err            error
+}
+
+func (s *fakeTrackerService) ImportIssues(
+	ctx context.Context, issues []*domain.Issue,
+) error {
+	for i, issue := range issues {
+		if s.err != nil && i > 0 {
+			return s.err
+		}
+		if issue.Title != "Rejected" {
+			issue.ID = "PROJ-" + strconv.Itoa(i+1)
+			s.importedIssues = append(s.importedIssues, issue)
+		}
+	}
+	return s.err
+}
+
+func (s *fakeTrackerService) ExportIssues(
+	ctx context.Context, query string,
+) ([]*domain.Issue, error) {
+	return nil, nil
+}
+
+func (s *fakeTrackerService) TestConnection(ctx context.Context) error {
+	return nil
+}
+
+func writeImportFiles(t *testing.T, dir string) []string {
+	files := []struct {
+		name     string
+		contents string
+	}{
+		{"a.md", "[Bug] First\n\n---\n\n[Chore] Rejected\n"},
+		{"b.md", "[Spike] Look\n"},
+		{"c.md", "[Feature] Second\n"},
+	}
+	paths := []string{}
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(f.contents), 0644))
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestRunImport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "issuez-import-cmd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	paths := writeImportFiles(t, dir)
+
+	trackerService := &fakeTrackerService{}
+	importer := issuez.NewImporter(
+		trackerService, issuez.ImportFileOptions{},
+	)
+	var out bytes.Buffer
+	exitCode := runImport(
+		context.Background(), &out, importer, paths, false,
+	)
+
+	// the other files are imported, but the parse failure is the exit code
+	require.Equal(t, 1, exitCode)
+	require.Len(t, trackerService.importedIssues, 2)
+	lines := strings.Split(out.String(), "\n")
+	require.Len(t, lines, 9)
+	require.True(t, strings.HasPrefix(
+		lines[1], "Failed to parse markdown file '"+paths[1]+"': ",
+	))
+	require.Equal(t, []string{
+		"Found 2 issues in the markdown file '" + paths[0] + "'",
+		"Found 1 issues in the markdown file '" + paths[2] + "'",
+		"Imported issues from '" + paths[0] + "':",
+		"- Bug (PROJ-1): First",
+		"- Chore (FAILED): Rejected",
+		"Imported issues from '" + paths[2] + "':",
+		"- User Story (PROJ-3): Second",
+		"",
+	}, append(lines[:1:1], lines[2:]...))
+}
+
+func TestRunImportFailFast(t *testing.T) {
+	dir, err := ioutil.TempDir("", "issuez-import-cmd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	paths := writeImportFiles(t, dir)
+
+	trackerService := &fakeTrackerService{}
+	importer := issuez.NewImporter(
+		trackerService, issuez.ImportFileOptions{},
+	)
+	var out bytes.Buffer
+	exitCode := runImport(
+		context.Background(), &out, importer, paths, true,
+	)
+
+	require.Equal(t, 1, exitCode)
+	require.Empty(t, trackerService.importedIssues)
+	require.Contains(t, out.String(), "Failed to parse markdown file '"+
+		paths[1]+"'")
+	require.NotContains(t, out.String(), paths[2])
+	require.NotContains(t, out.String(), "Imported issues")
+}
+
+func TestRunImportError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "issuez-import-cmd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	paths := writeImportFiles(t, dir)
+
+	trackerService := &fakeTrackerService{err: errors.New("Cancelled")}
+	importer := issuez.NewImporter(
+		trackerService, issuez.ImportFileOptions{},
+	)
+	var out bytes.Buffer
+	exitCode := runImport(
+		context.Background(), &out, importer,
+		[]string{paths[0], paths[2]}, false,
+	)
+
+	// the issues which were not imported are not listed as failed
+	require.Equal(t, 1, exitCode)
+	require.Equal(t, "Found 2 issues in the markdown file '"+paths[0]+"'\n"+
+		"Found 1 issues in the markdown file '"+paths[2]+"'\n"+
+		"Failed to import issues: Cancelled\n"+
+		"Imported issues from '"+paths[0]+"':\n"+
+		"- Bug (PROJ-1): First\n", out.String())
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/glestaris/issuez"
+	"github.com/spf13/cobra"
+)
+
+var renderVars []string
+
+func init() {
+	renderCmd.PersistentFlags().StringArrayVar(
+		&renderVars, "var", []string{},
+		"Set a template variable, as <name>=<value>",
+	)
+	rootCmd.AddCommand(renderCmd)
+}
+
+var renderCmd = &cobra.Command{
+	Use:   "render <Path to import file or - for stdin>",
+	Short: "Prints an import file with its includes and template expanded",
+	Args:  cobra.ExactArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// rendering does not connect to the tracker
+		unrequireTrackerFlags(cmd)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		vars, err := parseVars(renderVars)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+
+		data, err := issuez.LoadImportFile(
+			args[0], issuez.ImportFileOptions{Vars: vars},
+		)
+		if err != nil {
+			fmt.Fprintf(
+				os.Stderr, "Failed to render '%s': %s\n",
+				issuez.ImportFileName(args[0]), err,
+			)
+			os.Exit(1)
+		}
+
+		os.Stdout.Write(data)
+	},
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/tracker"
+	"github.com/spf13/cobra"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+)
+
+var (
+	trackerType        string
+	trackerAPIHost     string
+	trackerAPIUsername string
+	trackerAPIToken    string
+	trackerRepository  string
+	requestTimeout     time.Duration
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "issuez",
+    Short: "Importing Tickets to Jira from Markdown.",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Please use one of the subcommands.")
+	},
+	Version: "1.0.0-beta.01",
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(
+		&trackerType, "tracker", "jira",
+		"Tracker to connect to (see the trackers command)",
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&trackerAPIHost, "api", "a", "",
+		"JIRA host or GitHub API URL to use (defaults to api.github.com"+
+			" for GitHub)",
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&trackerAPIUsername, "username", "u", "",
+		"Username to use to connect to JIRA",
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&trackerAPIToken, "token", "t", "",
+		"API token to use to connect to the tracker",
+	)
+	rootCmd.MarkPersistentFlagRequired("token")
+	rootCmd.PersistentFlags().StringVar(
+		&trackerRepository, "repository", "",
+		"GitHub repository of the issues, as <owner>/<name>",
+	)
+	rootCmd.PersistentFlags().DurationVar(
+		&requestTimeout, "timeout", 0,
+		"Timeout of each request to the tracker, e.g. 30s (0 for no"+
+			" timeout)",
+	)
+}
+
+// trackerNames are the names of the trackers in messages.
+var trackerNames = map[string]string{"jira": "JIRA", "github": "GitHub"}
+
+func trackerName() string {
+	if name, ok := trackerNames[trackerType]; ok {
+		return name
+	}
+	return trackerType
+}
+
+// flagSetting is a setting of the config of a tracker set by a flag.
+type flagSetting struct {
+	key   string
+	value string
+}
+
+// newTrackerService returns the service of the tracker of --tracker,
+// configured by the connection flags and the settings, which are keyed by
+// the flags setting them. The settings the tracker does not have are left
+// out, unless their flags were set.
+func newTrackerService(
+	cmd *cobra.Command, settings map[string]flagSetting,
+) (tracker.TrackerService, error) {
+	backend, ok := tracker.LookupBackend(trackerType)
+	if !ok {
+		return nil, fmt.Errorf("Unknown tracker '%s'", trackerType)
+	}
+
+	flagSettings := map[string]flagSetting{
+		"api":        {"apiHost", trackerAPIHost},
+		"username":   {"apiUsername", trackerAPIUsername},
+		"token":      {"apiToken", trackerAPIToken},
+		"repository": {"repository", trackerRepository},
+		"timeout":    {"requestTimeout", requestTimeout.String()},
+	}
+	for flag, setting := range settings {
+		flagSettings[flag] = setting
+	}
+	flags := make([]string, 0, len(flagSettings))
+	for flag := range flagSettings {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+
+	keys := map[string]bool{}
+	for _, setting := range backend.Settings {
+		keys[setting.Key] = true
+	}
+	config := map[string]string{}
+	for _, flag := range flags {
+		setting := flagSettings[flag]
+		if keys[setting.key] {
+			config[setting.key] = setting.value
+		} else if cmd.Flags().Changed(flag) {
+			return nil, fmt.Errorf(
+				"The %s tracker has no --%s setting", trackerType, flag,
+			)
+		}
+	}
+
+	// the missing settings are reported as the flags setting them
+	for _, setting := range backend.Settings {
+		if !setting.Required || config[setting.Key] != "" {
+			continue
+		}
+		for _, flag := range flags {
+			if flagSettings[flag].key == setting.Key {
+				return nil, fmt.Errorf(
+					"The %s tracker needs --%s", trackerType, flag,
+				)
+			}
+		}
+	}
+
+	return tracker.NewTrackerService(domain.Tracker{
+		Type:   trackerType,
+		Config: config,
+	})
+}
+
+// unrequireTrackerFlags lets the command, which does not connect to the
+// tracker, run without the flags of the connection.
+func unrequireTrackerFlags(cmd *cobra.Command) {
+	for _, name := range []string{"api", "username", "token"} {
+		flag := cmd.Flags().Lookup(name)
+		delete(flag.Annotations, cobra.BashCompOneRequiredFlag)
+	}
+}
+
+// commandContext returns the context of the requests of a command, which is
+// cancelled on Ctrl-C. A second Ctrl-C stops the command at once.
+func commandContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		select {
+		case <-interrupts:
+			fmt.Fprintln(os.Stderr, "Cancelling...")
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(interrupts)
+	}()
+	return ctx, cancel
+}
+
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(testConnectionCmd)
+}
+
+var testConnectionCmd = &cobra.Command{
+	Use:   "test-connection",
+	Short: "Tests the tracker connection",
+	Run: func(cmd *cobra.Command, args []string) {
+		trackerService, err := newTrackerService(cmd, nil)
+		if err != nil {
+			fmt.Printf(
+				"Failed to initialise tracker service for %s: %s\n",
+				trackerName(), err,
+			)
+			os.Exit(1)
+		}
+
+		ctx, cancel := commandContext()
+		defer cancel()
+		if err := trackerService.TestConnection(ctx); err != nil {
+			fmt.Printf("Failed to connect to %s: %s\n", trackerName(), err)
+			os.Exit(1)
+		}
+
+		fmt.Println("OK")
+	},
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/glestaris/issuez/tracker"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(trackersCmd)
+}
+
+var trackersCmd = &cobra.Command{
+	Use:   "trackers",
+	Short: "Lists the tracker backends and their settings",
+	Args:  cobra.NoArgs,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// listing the backends does not connect to a tracker
+		unrequireTrackerFlags(cmd)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for i, backend := range tracker.Backends() {
+			if i != 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "%s: %s\n", backend.Type, backend.Description)
+			for _, setting := range backend.Settings {
+				description := setting.Description
+				if setting.Required {
+					description += " (required)"
+				} else if setting.Default != "" {
+					description += fmt.Sprintf(
+						" (defaults to %s)", setting.Default,
+					)
+				}
+				fmt.Fprintf(
+					w, "  %s\t%s\t%s\n",
+					setting.Key, setting.Type, description,
+				)
+			}
+		}
+		w.Flush()
+	},
+}
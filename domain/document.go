@@ -1,5 +1,7 @@
 package domain
 
+import "net/url"
+
 func NewDocument() Document {
 	return Document{}
 }
@@ -19,6 +21,18 @@ const (
 	DocumentNodeTypeList
 	// Code Block
 	DocumentNodeTypeCodeBlock
+	// Block Quote
+	DocumentNodeTypeBlockQuote
+	// Panel
+	DocumentNodeTypePanel
+	// Table
+	DocumentNodeTypeTable
+	// Image
+	DocumentNodeTypeImage
+	// Expand
+	DocumentNodeTypeExpand
+	// Rule
+	DocumentNodeTypeRule
 )
 
 func (t DocumentNodeType) String() string {
@@ -31,6 +45,18 @@ func (t DocumentNodeType) String() string {
 		return "Paragraph"
 	case DocumentNodeTypeCodeBlock:
 		return "Code Block"
+	case DocumentNodeTypeBlockQuote:
+		return "Block Quote"
+	case DocumentNodeTypePanel:
+		return "Panel"
+	case DocumentNodeTypeTable:
+		return "Table"
+	case DocumentNodeTypeImage:
+		return "Image"
+	case DocumentNodeTypeExpand:
+		return "Expand"
+	case DocumentNodeTypeRule:
+		return "Rule"
 	default:
 		return "Unknown"
 	}
@@ -50,6 +76,21 @@ type DocumentNode struct {
 
 	// Code block
 	*CodeBlockData
+
+	// Block quote
+	*BlockQuoteData
+
+	// Panel
+	*PanelData
+
+	// Table
+	*TableData
+
+	// Image
+	*ImageData
+
+	// Expand
+	*ExpandData
 }
 
 /******************************************************************************
@@ -115,31 +156,105 @@ type TextMode struct {
 	Italics       bool
 	Strikethrough bool
 	Code          bool
+	Underline     bool
+	Subscript     bool
+	Superscript   bool
+
+	// Color is the colour of the text as a lowercase hex RGB code (e.g.
+	// "#ff5630"), or empty for the default colour.
+	Color string
 }
 
+type TextElementType int
+
+const (
+	// Text
+	TextElementTypeText TextElementType = iota
+	// Hard breaks are line breaks which are kept as they are, unlike the
+	// line breaks of the text. Their text is a new line.
+	TextElementTypeHardBreak
+	// Mentions of users. Their text is the email address or the name of the
+	// user.
+	TextElementTypeMention
+	// Emojis. Their text is the short name of the emoji (e.g. "warning").
+	TextElementTypeEmoji
+	// Dates. Their text is the date in ISO format (e.g. "2026-11-01").
+	TextElementTypeDate
+)
+
 type TextElement struct {
+	Type TextElementType
 	Text string
 	Mode TextMode
 
 	// Links
 	LinkURL string
+
+	// Mentions
+	AccountID string
+}
+
+// addElement appends the element to the container. Text following an
+// element with the same formatting is merged into it, so that the same text
+// always results in the same elements.
+func (t *TextContainer) addElement(te TextElement) {
+	if len(t.Elements) != 0 {
+		last := &t.Elements[len(t.Elements)-1]
+		if last.Type == TextElementTypeText &&
+			te.Type == TextElementTypeText &&
+			last.Mode == te.Mode && last.LinkURL == te.LinkURL {
+			last.Text += te.Text
+			return
+		}
+	}
+	t.Elements = append(t.Elements, te)
 }
 
 func (t *TextContainer) AddText(text string, mode TextMode) {
-	te := TextElement{
+	t.addElement(TextElement{
 		Text: text,
 		Mode: mode,
-	}
-	t.Elements = append(t.Elements, te)
+	})
 }
 
 func (t *TextContainer) AddLink(text string, linkURL string, mode TextMode) {
-	te := TextElement{
+	t.addElement(TextElement{
 		Text:    text,
 		Mode:    mode,
 		LinkURL: linkURL,
-	}
-	t.Elements = append(t.Elements, te)
+	})
+}
+
+func (t *TextContainer) AddHardBreak() {
+	t.Elements = append(t.Elements, TextElement{
+		Type: TextElementTypeHardBreak,
+		Text: "\n",
+	})
+}
+
+// AddMention adds a mention of a user, given by email address or name. The
+// account ID of the user is set once the user is found in the tracker.
+func (t *TextContainer) AddMention(user string, accountID string) {
+	t.Elements = append(t.Elements, TextElement{
+		Type:      TextElementTypeMention,
+		Text:      user,
+		AccountID: accountID,
+	})
+}
+
+func (t *TextContainer) AddEmoji(shortName string) {
+	t.Elements = append(t.Elements, TextElement{
+		Type: TextElementTypeEmoji,
+		Text: shortName,
+	})
+}
+
+// AddDate adds a date, given in ISO format (e.g. "2026-11-01").
+func (t *TextContainer) AddDate(date string) {
+	t.Elements = append(t.Elements, TextElement{
+		Type: TextElementTypeDate,
+		Text: date,
+	})
 }
 
 /******************************************************************************
@@ -164,9 +279,22 @@ func (d *Document) AddParagraph() *TextContainer {
  * Lists
  *****************************************************************************/
 
+// ListData keeps the items of a list. Task lists are unordered lists whose
+// items can be checked.
 type ListData struct {
-	IsOrdered bool
-	Items     []TextContainer
+	IsOrdered  bool
+	IsTaskList bool
+	Items      []ListItem
+}
+
+// ListItem is an item of a list. The text of the item is followed by the
+// blocks nested in it (e.g. lists or more paragraphs), if any.
+type ListItem struct {
+	TextContainer
+	Document
+
+	// IsChecked is only set for items of task lists
+	IsChecked bool
 }
 
 func (d *Document) AddOrderedList() *ListData {
@@ -193,7 +321,319 @@ func (d *Document) AddUnorderedList() *ListData {
 	return data
 }
 
-func (l *ListData) AddItem() *TextContainer {
-	l.Items = append(l.Items, TextContainer{})
+func (d *Document) AddTaskList() *ListData {
+	data := &ListData{
+		IsTaskList: true,
+	}
+	node := DocumentNode{
+		Type:     DocumentNodeTypeList,
+		ListData: data,
+	}
+	d.Nodes = append(d.Nodes, node)
+	return data
+}
+
+func (l *ListData) AddItem() *ListItem {
+	l.Items = append(l.Items, ListItem{})
 	return &l.Items[len(l.Items)-1]
 }
+
+/******************************************************************************
+ * Block Quotes
+ *****************************************************************************/
+
+// BlockQuoteData keeps the blocks of a quote.
+type BlockQuoteData struct {
+	Document
+}
+
+func (d *Document) AddBlockQuote() *BlockQuoteData {
+	data := &BlockQuoteData{}
+	node := DocumentNode{
+		Type:           DocumentNodeTypeBlockQuote,
+		BlockQuoteData: data,
+	}
+	d.Nodes = append(d.Nodes, node)
+	return data
+}
+
+/******************************************************************************
+ * Panels
+ *****************************************************************************/
+
+type PanelType int
+
+const (
+	PanelTypeInfo PanelType = iota
+	PanelTypeNote
+	PanelTypeSuccess
+	PanelTypeWarning
+	PanelTypeError
+)
+
+func (t PanelType) String() string {
+	switch t {
+	case PanelTypeInfo:
+		return "Info"
+	case PanelTypeNote:
+		return "Note"
+	case PanelTypeSuccess:
+		return "Success"
+	case PanelTypeWarning:
+		return "Warning"
+	case PanelTypeError:
+		return "Error"
+	default:
+		return "Unknown"
+	}
+}
+
+// PanelData keeps the blocks of a panel: a highlighted block of the
+// description, such as a note or a warning.
+type PanelData struct {
+	PanelType PanelType
+	Document
+}
+
+func (d *Document) AddPanel(panelType PanelType) *PanelData {
+	data := &PanelData{
+		PanelType: panelType,
+	}
+	node := DocumentNode{
+		Type:      DocumentNodeTypePanel,
+		PanelData: data,
+	}
+	d.Nodes = append(d.Nodes, node)
+	return data
+}
+
+/******************************************************************************
+ * Expands
+ *****************************************************************************/
+
+// ExpandData keeps the blocks of an expand: a section of the description
+// which is collapsed under its title, such as a long stack trace.
+type ExpandData struct {
+	Title string
+	Document
+}
+
+func (d *Document) AddExpand(title string) *ExpandData {
+	data := &ExpandData{
+		Title: title,
+	}
+	node := DocumentNode{
+		Type:       DocumentNodeTypeExpand,
+		ExpandData: data,
+	}
+	d.Nodes = append(d.Nodes, node)
+	return data
+}
+
+/******************************************************************************
+ * Tables
+ *****************************************************************************/
+
+type TableAlignment int
+
+const (
+	TableAlignmentLeft TableAlignment = iota
+	TableAlignmentCenter
+	TableAlignmentRight
+)
+
+// TableData keeps the rows of a table and the alignment of its columns.
+// Columns without an alignment are aligned to the left.
+type TableData struct {
+	Alignments []TableAlignment
+	Rows       []TableRow
+}
+
+// TableRow is a row of a table. Header rows are usually the first rows of a
+// table.
+type TableRow struct {
+	IsHeader bool
+	Cells    []TextContainer
+}
+
+// Alignment returns the alignment of a column of the table.
+func (t *TableData) Alignment(column int) TableAlignment {
+	if column < len(t.Alignments) {
+		return t.Alignments[column]
+	}
+	return TableAlignmentLeft
+}
+
+func (d *Document) AddTable(alignments ...TableAlignment) *TableData {
+	data := &TableData{
+		Alignments: alignments,
+	}
+	node := DocumentNode{
+		Type:      DocumentNodeTypeTable,
+		TableData: data,
+	}
+	d.Nodes = append(d.Nodes, node)
+	return data
+}
+
+func (t *TableData) AddHeaderRow() *TableRow {
+	t.Rows = append(t.Rows, TableRow{IsHeader: true})
+	return &t.Rows[len(t.Rows)-1]
+}
+
+func (t *TableData) AddRow() *TableRow {
+	t.Rows = append(t.Rows, TableRow{})
+	return &t.Rows[len(t.Rows)-1]
+}
+
+func (r *TableRow) AddCell() *TextContainer {
+	r.Cells = append(r.Cells, TextContainer{})
+	return &r.Cells[len(r.Cells)-1]
+}
+
+/******************************************************************************
+ * Rules
+ *****************************************************************************/
+
+// AddRule adds a horizontal rule, which divides the blocks before it from
+// the blocks after it.
+func (d *Document) AddRule() {
+	d.Nodes = append(d.Nodes, DocumentNode{Type: DocumentNodeTypeRule})
+}
+
+// HasRules checks whether the document has horizontal rules, including the
+// rules nested in lists, quotes, panels and expands.
+func (d *Document) HasRules() bool {
+	for _, node := range d.Nodes {
+		switch node.Type {
+		case DocumentNodeTypeRule:
+			return true
+		case DocumentNodeTypeList:
+			for _, item := range node.ListData.Items {
+				if item.HasRules() {
+					return true
+				}
+			}
+		case DocumentNodeTypeBlockQuote:
+			if node.BlockQuoteData.HasRules() {
+				return true
+			}
+		case DocumentNodeTypePanel:
+			if node.PanelData.HasRules() {
+				return true
+			}
+		case DocumentNodeTypeExpand:
+			if node.ExpandData.HasRules() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/******************************************************************************
+ * Images
+ *****************************************************************************/
+
+// ImageData keeps an image of the description. The source of the image is
+// either the path of a local file or the URL of a remote image.
+type ImageData struct {
+	Source  string
+	AltText string
+
+	// AttachmentURL is the URL of the attachment a local image is uploaded
+	// as, if it is, and MediaID the ID of its file in the media store of the
+	// tracker, if the tracker has one
+	AttachmentURL string
+	MediaID       string
+}
+
+// IsLocal checks whether the source of the image is the path of a local
+// file.
+func (i *ImageData) IsLocal() bool {
+	sourceURL, err := url.Parse(i.Source)
+	return err != nil || sourceURL.Scheme == ""
+}
+
+func (d *Document) AddImage(source string, altText string) {
+	node := DocumentNode{
+		Type: DocumentNodeTypeImage,
+		ImageData: &ImageData{
+			Source:  source,
+			AltText: altText,
+		},
+	}
+	d.Nodes = append(d.Nodes, node)
+}
+
+// Images returns the images of the document, including the images nested in
+// lists, quotes, panels and expands.
+func (d *Document) Images() []*ImageData {
+	images := []*ImageData{}
+	for _, node := range d.Nodes {
+		switch node.Type {
+		case DocumentNodeTypeImage:
+			images = append(images, node.ImageData)
+		case DocumentNodeTypeList:
+			for _, item := range node.ListData.Items {
+				images = append(images, item.Images()...)
+			}
+		case DocumentNodeTypeBlockQuote:
+			images = append(images, node.BlockQuoteData.Images()...)
+		case DocumentNodeTypePanel:
+			images = append(images, node.PanelData.Images()...)
+		case DocumentNodeTypeExpand:
+			images = append(images, node.ExpandData.Images()...)
+		}
+	}
+	return images
+}
+
+/******************************************************************************
+ * Text
+ *****************************************************************************/
+
+// TextContainers returns the text of the document: its paragraphs, the text
+// of its list items and the cells of its tables, including the text nested
+// in lists, quotes, panels and expands.
+func (d *Document) TextContainers() []*TextContainer {
+	tcs := []*TextContainer{}
+	for _, node := range d.Nodes {
+		switch node.Type {
+		case DocumentNodeTypeParagraph:
+			tcs = append(tcs, &node.ParagraphData.Content)
+		case DocumentNodeTypeList:
+			for i := range node.ListData.Items {
+				item := &node.ListData.Items[i]
+				tcs = append(tcs, &item.TextContainer)
+				tcs = append(tcs, item.Document.TextContainers()...)
+			}
+		case DocumentNodeTypeBlockQuote:
+			tcs = append(tcs, node.BlockQuoteData.TextContainers()...)
+		case DocumentNodeTypePanel:
+			tcs = append(tcs, node.PanelData.TextContainers()...)
+		case DocumentNodeTypeExpand:
+			tcs = append(tcs, node.ExpandData.TextContainers()...)
+		case DocumentNodeTypeTable:
+			for _, row := range node.TableData.Rows {
+				for i := range row.Cells {
+					tcs = append(tcs, &row.Cells[i])
+				}
+			}
+		}
+	}
+	return tcs
+}
+
+// Mentions returns the mentions of the document, wherever they are in it.
+func (d *Document) Mentions() []*TextElement {
+	mentions := []*TextElement{}
+	for _, tc := range d.TextContainers() {
+		for i := range tc.Elements {
+			if tc.Elements[i].Type == TextElementTypeMention {
+				mentions = append(mentions, &tc.Elements[i])
+			}
+		}
+	}
+	return mentions
+}
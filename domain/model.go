@@ -6,6 +6,9 @@ const (
 	IssueTypeChore IssueType = iota
 	IssueTypeStory
 	IssueTypeBug
+	// IssueTypeRelease marks the end of the issues of a release, as in
+	// Prolific and Pivotal Tracker
+	IssueTypeRelease
 )
 
 func (it IssueType) String() string {
@@ -16,6 +19,8 @@ func (it IssueType) String() string {
 		return "User Story"
 	case IssueTypeBug:
 		return "Bug"
+	case IssueTypeRelease:
+		return "Release"
 	default:
 		return "Unknown Type"
 	}
@@ -34,6 +39,10 @@ type Label struct {
 	Label string
 }
 
+type Attachment struct {
+	Path string
+}
+
 type Issue struct {
 	ID          string
 	Type        IssueType
@@ -41,4 +50,5 @@ type Issue struct {
 	Description *Document
 	Epic        *Epic
 	Labels      []Label
+	Attachments []Attachment
 }
@@ -0,0 +1,86 @@
+package issuez
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/markdown"
+)
+
+// WriteExportFile writes the issues in the markdown format read by
+// markdown.ParseImportFileWithOptions, separated by the separator.
+// Descriptions with horizontal rules cannot be written with
+// markdown.LegacySeparator.
+func WriteExportFile(
+	w io.Writer, issues []*domain.Issue, separator string,
+) error {
+	if separator == "" {
+		separator = markdown.LegacySeparator
+	}
+	for _, issue := range issues {
+		if separator == markdown.LegacySeparator &&
+			issue.Description != nil &&
+			issue.Description.HasRules() {
+			return fmt.Errorf(
+				"Issue '%s' has horizontal rules, which would separate issues"+
+					" (use another separator)", issue.Title,
+			)
+		}
+	}
+	for i, issue := range issues {
+		if i > 0 {
+			_, err := io.WriteString(w, "\n"+separator+"\n\n")
+			if err != nil {
+				return fmt.Errorf("Failed to write markdown file: %s", err)
+			}
+		}
+		if _, err := io.WriteString(w, renderIssue(issue)); err != nil {
+			return fmt.Errorf("Failed to write markdown file: %s", err)
+		}
+	}
+	return nil
+}
+
+func issueTypeTag(issueType domain.IssueType) string {
+	switch issueType {
+	case domain.IssueTypeBug:
+		return "Bug"
+	case domain.IssueTypeChore:
+		return "Chore"
+	case domain.IssueTypeRelease:
+		return "Release"
+	default:
+		return "Story"
+	}
+}
+
+func renderIssue(issue *domain.Issue) string {
+	blocks := []string{
+		fmt.Sprintf(
+			"[%s] %s", issueTypeTag(issue.Type), markdown.EscapeText(issue.Title),
+		),
+	}
+
+	if issue.Description != nil && len(issue.Description.Nodes) != 0 {
+		blocks = append(blocks, markdown.RenderDocument(issue.Description))
+	}
+
+	footer := []string{}
+	if issue.Epic != nil {
+		footer = append(footer, "Epic: "+issue.Epic.ID)
+	}
+	if len(issue.Labels) != 0 {
+		labels := make([]string, len(issue.Labels))
+		for i, label := range issue.Labels {
+			labels[i] = label.Label
+		}
+		footer = append(footer, "Labels: "+strings.Join(labels, ", "))
+	}
+	if len(footer) != 0 {
+		blocks = append(blocks, strings.Join(footer, "\n"))
+	}
+
+	return strings.Join(blocks, "\n\n") + "\n"
+}
@@ -0,0 +1,134 @@
+package issuez_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/glestaris/issuez"
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/markdown"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportFileWriter(t *testing.T) {
+	description := &domain.Document{}
+	description.AddHeading(domain.HeadingLevel2, "Steps")
+	p := description.AddParagraph()
+	p.AddText("Hello ", domain.TextMode{})
+	p.AddText("bold", domain.TextMode{Bold: true})
+	p.AddText(" and ", domain.TextMode{})
+	p.AddLink("link", "https://google.com", domain.TextMode{Italics: true})
+	l := description.AddOrderedList()
+	l.AddItem().AddText("One", domain.TextMode{})
+	l.AddItem().AddText("Two", domain.TextMode{Code: true})
+	description.AddCodeBlock("python", "x = 12\n")
+
+	out := &bytes.Buffer{}
+	err := issuez.WriteExportFile(out, []*domain.Issue{
+		{
+			ID:          "TEST-1",
+			Type:        domain.IssueTypeBug,
+			Title:       "A bug",
+			Description: description,
+			Epic:        &domain.Epic{ID: "TEST-100"},
+			Labels: []domain.Label{
+				{Label: "label-1"},
+				{Label: "label-2"},
+			},
+		},
+		{
+			ID:    "TEST-2",
+			Type:  domain.IssueTypeChore,
+			Title: "A chore",
+		},
+	}, markdown.LegacySeparator)
+	require.NoError(t, err)
+	require.Equal(t, "[Bug] A bug\n\n"+
+		"## Steps\n\n"+
+		"Hello **bold** and [_link_](https://google.com)\n\n"+
+		"1. One\n"+
+		"2. `Two`\n\n"+
+		"<!-- -->\n\n"+
+		"```python\n"+
+		"x = 12\n"+
+		"```\n\n"+
+		"Epic: TEST-100\n"+
+		"Labels: label-1, label-2\n"+
+		"\n---\n\n"+
+		"[Chore] A chore\n", out.String())
+}
+
+func TestExportFileWriterRoundTrip(t *testing.T) {
+	description := &domain.Document{}
+	description.AddHeading(domain.HeadingLevel1, "Title")
+	p := description.AddParagraph()
+	p.AddText("Some ", domain.TextMode{})
+	p.AddText("bold and italics", domain.TextMode{Bold: true, Italics: true})
+	p.AddText(" and ", domain.TextMode{})
+	p.AddText("struck", domain.TextMode{Strikethrough: true})
+	p.AddText(" text", domain.TextMode{})
+	l := description.AddUnorderedList()
+	l.AddItem().AddText("A", domain.TextMode{})
+	l.AddItem().AddLink("B", "https://google.com", domain.TextMode{})
+
+	issues := []*domain.Issue{
+		{
+			Type:        domain.IssueTypeStory,
+			Title:       "A story with *stars* and [brackets]",
+			Description: description,
+			Epic:        &domain.Epic{ID: "TEST-100"},
+		},
+		{
+			Type:   domain.IssueTypeBug,
+			Title:  "A bug",
+			Labels: []domain.Label{{Label: "label-1"}},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(
+		t, issuez.WriteExportFile(out, issues, markdown.LegacySeparator),
+	)
+
+	parsedIssues, err := markdown.ParseImportFile(out)
+	require.NoError(t, err)
+	require.Equal(t, issues, parsedIssues)
+}
+
+func TestExportFileWriterSeparator(t *testing.T) {
+	description := &domain.Document{}
+	description.AddParagraph().AddText("Before", domain.TextMode{})
+	description.AddRule()
+	description.AddParagraph().AddText("After", domain.TextMode{})
+	issues := []*domain.Issue{
+		{
+			Type:        domain.IssueTypeStory,
+			Title:       "A story",
+			Description: description,
+		},
+		{
+			Type:  domain.IssueTypeBug,
+			Title: "A bug",
+		},
+	}
+
+	out := &bytes.Buffer{}
+	require.Error(
+		t, issuez.WriteExportFile(out, issues, markdown.LegacySeparator),
+	)
+
+	out = &bytes.Buffer{}
+	require.NoError(t, issuez.WriteExportFile(out, issues, "<!-- issue -->"))
+	require.Equal(t, "[Story] A story\n\n"+
+		"Before\n\n"+
+		"---\n\n"+
+		"After\n"+
+		"\n<!-- issue -->\n\n"+
+		"[Bug] A bug\n", out.String())
+
+	parsedIssues, err := markdown.ParseImportFileWithOptions(
+		out, markdown.ParserOptions{Separator: "<!-- issue -->"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, issues, parsedIssues)
+}
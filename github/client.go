@@ -0,0 +1,381 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultAPIHost is the host of the GitHub API. The API of GitHub Enterprise
+// servers is at https://<host>/api/v3.
+const DefaultAPIHost = "https://api.github.com"
+
+// pageSize is the number of results requested per page from the GitHub API.
+const pageSize = 100
+
+type Client struct {
+	httpClient *http.Client
+	// host
+	host string
+	// auth
+	token string
+}
+
+func NewGitHubClient(
+	apiHost string, apiToken string, httpClient *http.Client,
+) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		httpClient: httpClient,
+		host:       apiHost,
+		token:      apiToken,
+	}
+}
+
+/******************************************************************************
+ * GitHub Issues
+ *****************************************************************************/
+
+// Issue is an issue of a repository. Its body is markdown.
+type Issue struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Labels    []Label    `json:"labels"`
+	Milestone *Milestone `json:"milestone"`
+
+	// PullRequest is set for the pull requests searches return, which are
+	// issues to the GitHub API
+	PullRequest *struct{} `json:"pull_request,omitempty"`
+}
+
+type Label struct {
+	Name string `json:"name"`
+}
+
+type issCreateReq struct {
+	Title     string   `json:"title"`
+	Body      string   `json:"body,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Milestone int      `json:"milestone,omitempty"`
+}
+
+// CreateIssue creates an issue in the repository, given as "owner/name",
+// and returns its number. A milestone of 0 is no milestone.
+func (c *Client) CreateIssue(
+	ctx context.Context, repository string, title string, body string,
+	labels []string, milestone int,
+) (int, error) {
+	reqBodyBytes, err := json.Marshal(issCreateReq{
+		Title:     title,
+		Body:      body,
+		Labels:    labels,
+		Milestone: milestone,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("Failed to serialize request body: %s", err)
+	}
+
+	req, resp, err := c.performRequest(
+		ctx, "POST", repositoryPath(repository)+"/issues", reqBodyBytes,
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 201 {
+		c.logFailedRequest(req, resp)
+		return 0, fmt.Errorf("Failed to create issue: %s", resp.Status)
+	}
+
+	issue := &Issue{}
+	if err := json.NewDecoder(resp.Body).Decode(issue); err != nil {
+		return 0, fmt.Errorf("Failed to parse API response: %s", err)
+	}
+
+	return issue.Number, nil
+}
+
+// GetIssue returns the issue of the repository with the number.
+func (c *Client) GetIssue(
+	ctx context.Context, repository string, number int,
+) (*Issue, error) {
+	req, resp, err := c.performRequest(
+		ctx, "GET", issuePath(repository, number), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		c.logFailedRequest(req, resp)
+		return nil, fmt.Errorf("Failed to get issue: %s", resp.Status)
+	}
+
+	issue := &Issue{}
+	if err := json.NewDecoder(resp.Body).Decode(issue); err != nil {
+		return nil, fmt.Errorf("Failed to parse API response: %s", err)
+	}
+
+	return issue, nil
+}
+
+type issUpdateReq struct {
+	Body string `json:"body"`
+}
+
+// UpdateIssueBody replaces the body of the issue of the repository with the
+// number.
+func (c *Client) UpdateIssueBody(
+	ctx context.Context, repository string, number int, body string,
+) error {
+	reqBodyBytes, err := json.Marshal(issUpdateReq{Body: body})
+	if err != nil {
+		return fmt.Errorf("Failed to serialize request body: %s", err)
+	}
+
+	req, resp, err := c.performRequest(
+		ctx, "PATCH", issuePath(repository, number), reqBodyBytes,
+	)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		c.logFailedRequest(req, resp)
+		return fmt.Errorf("Failed to update issue: %s", resp.Status)
+	}
+
+	return nil
+}
+
+type issSearchResp struct {
+	TotalCount int      `json:"total_count"`
+	Items      []*Issue `json:"items"`
+}
+
+// SearchIssues returns the issues matching the query of the GitHub search
+// syntax, e.g. "repo:owner/name is:open label:bug". Pull requests are left
+// out.
+func (c *Client) SearchIssues(
+	ctx context.Context, query string,
+) ([]*Issue, error) {
+	foundIssues := []*Issue{}
+	found := 0
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("q", query)
+		params.Set("per_page", strconv.Itoa(pageSize))
+		params.Set("page", strconv.Itoa(page))
+
+		req, resp, err := c.performRequest(
+			ctx, "GET", "/search/issues?"+params.Encode(), nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			c.logFailedRequest(req, resp)
+			resp.Body.Close()
+			return nil, fmt.Errorf(
+				"Failed to search issues: %s", resp.Status,
+			)
+		}
+
+		respBody := issSearchResp{}
+		err = json.NewDecoder(resp.Body).Decode(&respBody)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse API response: %s", err)
+		}
+
+		for _, issue := range respBody.Items {
+			if issue.PullRequest == nil {
+				foundIssues = append(foundIssues, issue)
+			}
+		}
+
+		found += len(respBody.Items)
+		if len(respBody.Items) < pageSize || found >= respBody.TotalCount {
+			break
+		}
+	}
+
+	return foundIssues, nil
+}
+
+/******************************************************************************
+ * GitHub Milestones
+ *****************************************************************************/
+
+// Milestone is a milestone of a repository, which issues can be part of.
+type Milestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+// Milestones returns the open and the closed milestones of the repository.
+func (c *Client) Milestones(
+	ctx context.Context, repository string,
+) ([]*Milestone, error) {
+	milestones := []*Milestone{}
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("state", "all")
+		params.Set("per_page", strconv.Itoa(pageSize))
+		params.Set("page", strconv.Itoa(page))
+
+		req, resp, err := c.performRequest(
+			ctx, "GET",
+			repositoryPath(repository)+"/milestones?"+params.Encode(), nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != 200 {
+			c.logFailedRequest(req, resp)
+			resp.Body.Close()
+			return nil, fmt.Errorf(
+				"Failed to list milestones: %s", resp.Status,
+			)
+		}
+
+		pageMilestones := []*Milestone{}
+		err = json.NewDecoder(resp.Body).Decode(&pageMilestones)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse API response: %s", err)
+		}
+
+		milestones = append(milestones, pageMilestones...)
+		if len(pageMilestones) < pageSize {
+			break
+		}
+	}
+
+	return milestones, nil
+}
+
+type milestoneCreateReq struct {
+	Title string `json:"title"`
+}
+
+// CreateMilestone creates a milestone of the repository.
+func (c *Client) CreateMilestone(
+	ctx context.Context, repository string, title string,
+) (*Milestone, error) {
+	reqBodyBytes, err := json.Marshal(milestoneCreateReq{Title: title})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to serialize request body: %s", err)
+	}
+
+	req, resp, err := c.performRequest(
+		ctx, "POST", repositoryPath(repository)+"/milestones", reqBodyBytes,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 201 {
+		c.logFailedRequest(req, resp)
+		return nil, fmt.Errorf(
+			"Failed to create milestone: %s", resp.Status,
+		)
+	}
+
+	milestone := &Milestone{}
+	if err := json.NewDecoder(resp.Body).Decode(milestone); err != nil {
+		return nil, fmt.Errorf("Failed to parse API response: %s", err)
+	}
+
+	return milestone, nil
+}
+
+/******************************************************************************
+ * Test GitHub API Connection
+ *****************************************************************************/
+
+func (c *Client) Test(ctx context.Context) error {
+	req, resp, err := c.performRequest(ctx, "GET", "/user", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		c.logFailedRequest(req, resp)
+		return fmt.Errorf(
+			"Failed to test the GitHub API connection: %s", resp.Status,
+		)
+	}
+
+	return nil
+}
+
+/******************************************************************************
+ * GitHub API Helpers
+ *****************************************************************************/
+
+// repositoryPath returns the API path of the repository, given as
+// "owner/name".
+func repositoryPath(repository string) string {
+	owner, name := repository, ""
+	if i := strings.Index(repository, "/"); i != -1 {
+		owner, name = repository[:i], repository[i+1:]
+	}
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(name)
+}
+
+func issuePath(repository string, number int) string {
+	return repositoryPath(repository) + "/issues/" + strconv.Itoa(number)
+}
+
+func (c *Client) performRequest(
+	ctx context.Context, method string, path string, body []byte,
+) (*http.Request, *http.Response, error) {
+	reqURL := fmt.Sprintf(
+		"%s/%s",
+		strings.TrimRight(c.host, "/"),
+		strings.TrimLeft(path, "/"),
+	)
+	req, err := http.NewRequestWithContext(
+		ctx, method, reqURL, bytes.NewBuffer(body),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to create request: %s", err)
+	}
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to perform request: %w", err)
+	}
+
+	return req, resp, nil
+}
+
+func (c *Client) logFailedRequest(req *http.Request, resp *http.Response) {
+	log.Printf(
+		"GitHub API request %s %s failed", req.Method, req.URL.String(),
+	)
+
+	log.Printf("\tStatus: '%s'", resp.Status)
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err == nil {
+		respBodyStr := string(respBody)
+		log.Printf("\tResponse body: '%s'", respBodyStr)
+	} else {
+		log.Printf("\tFailed read response body: %s", err)
+	}
+}
@@ -0,0 +1,168 @@
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/glestaris/issuez/github"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCreateIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "POST", r.Method)
+			require.Equal(t, "/api/v3/repos/owner/repo/issues", r.URL.Path)
+			require.Equal(t, "token abc123", r.Header.Get("Authorization"))
+
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{
+  "title": "A bug",
+  "body": "It *breaks*",
+  "labels": ["bug", "backend"],
+  "milestone": 3
+}`, string(body))
+
+			w.WriteHeader(201)
+			w.Write([]byte(`{"number": 42, "title": "A bug"}`))
+		},
+	))
+	defer server.Close()
+
+	client := github.NewGitHubClient(server.URL+"/api/v3/", "abc123", nil)
+	number, err := client.CreateIssue(
+		context.Background(), "owner/repo", "A bug", "It *breaks*",
+		[]string{"bug", "backend"}, 3,
+	)
+	require.NoError(t, err)
+	require.Equal(t, 42, number)
+}
+
+func TestClientCreateIssueError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(404)
+		},
+	))
+	defer server.Close()
+
+	client := github.NewGitHubClient(server.URL, "abc123", nil)
+	_, err := client.CreateIssue(
+		context.Background(), "owner/missing", "A bug", "", nil, 0,
+	)
+	require.EqualError(t, err, "Failed to create issue: 404 Not Found")
+}
+
+func TestClientSearchIssuesPaginates(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/search/issues", r.URL.Path)
+			require.Equal(t, "repo:owner/repo is:open", r.URL.Query().Get("q"))
+			page := r.URL.Query().Get("page")
+			pages = append(pages, page)
+
+			items := []map[string]interface{}{}
+			first, count := 1, 100
+			if page == "2" {
+				first, count = 101, 20
+			}
+			for i := first; i < first+count; i++ {
+				item := map[string]interface{}{
+					"number": i,
+					"title":  fmt.Sprintf("Issue %d", i),
+					"body":   nil,
+					"labels": []map[string]string{{"name": "bug"}},
+				}
+				if i == 2 {
+					item["pull_request"] = map[string]string{"url": "..."}
+				}
+				items = append(items, item)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"total_count": 120,
+				"items":       items,
+			})
+		},
+	))
+	defer server.Close()
+
+	client := github.NewGitHubClient(server.URL, "abc123", nil)
+	issues, err := client.SearchIssues(
+		context.Background(), "repo:owner/repo is:open",
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "2"}, pages)
+	require.Len(t, issues, 119)
+	require.Equal(t, 1, issues[0].Number)
+	require.Equal(t, 3, issues[1].Number)
+	require.Equal(t, "Issue 1", issues[0].Title)
+	require.Equal(t, "", issues[0].Body)
+	require.Equal(t, []github.Label{{Name: "bug"}}, issues[0].Labels)
+	require.Nil(t, issues[0].Milestone)
+}
+
+func TestClientMilestones(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/repos/owner/repo/milestones", r.URL.Path)
+			require.Equal(t, "all", r.URL.Query().Get("state"))
+			w.Write([]byte(`[
+  {"number": 1, "title": "v1.0", "state": "closed"},
+  {"number": 2, "title": "v2.0", "state": "open"}
+]`))
+		},
+	))
+	defer server.Close()
+
+	client := github.NewGitHubClient(server.URL, "abc123", nil)
+	milestones, err := client.Milestones(context.Background(), "owner/repo")
+	require.NoError(t, err)
+	require.Equal(t, []*github.Milestone{
+		{Number: 1, Title: "v1.0"},
+		{Number: 2, Title: "v2.0"},
+	}, milestones)
+}
+
+func TestClientUpdateIssueBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "PATCH", r.Method)
+			require.Equal(t, "/repos/owner/repo/issues/7", r.URL.Path)
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"body": "- [ ] #8\n"}`, string(body))
+			w.Write([]byte(`{"number": 7}`))
+		},
+	))
+	defer server.Close()
+
+	client := github.NewGitHubClient(server.URL, "abc123", nil)
+	err := client.UpdateIssueBody(
+		context.Background(), "owner/repo", 7, "- [ ] #8\n",
+	)
+	require.NoError(t, err)
+}
+
+func TestClientTest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/user", r.URL.Path)
+			w.WriteHeader(401)
+		},
+	))
+	defer server.Close()
+
+	client := github.NewGitHubClient(server.URL, "expired", nil)
+	err := client.Test(context.Background())
+	require.EqualError(
+		t, err,
+		"Failed to test the GitHub API connection: 401 Unauthorized",
+	)
+}
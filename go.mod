@@ -10,5 +10,5 @@ require (
 	github.com/stretchr/testify v1.5.1
 	github.com/trivago/tgo v1.0.7 // indirect
 	gopkg.in/andygrunwald/go-jira.v1 v1.8.0
-	gopkg.in/yaml.v2 v2.2.8 // indirect
+	gopkg.in/yaml.v2 v2.2.8
 )
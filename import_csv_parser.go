@@ -0,0 +1,197 @@
+package issuez
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/markdown"
+)
+
+// The fields of the issues CSV columns can hold
+const (
+	csvFieldSummary     = "summary"
+	csvFieldType        = "type"
+	csvFieldDescription = "description"
+	csvFieldEpic        = "epic"
+	csvFieldLabels      = "labels"
+	csvFieldAttachments = "attachments"
+)
+
+// csvHeaderFields maps the headers of the columns which are read without a
+// mapping to their fields. Headers are compared in lowercase.
+var csvHeaderFields = map[string]string{
+	"summary":     csvFieldSummary,
+	"title":       csvFieldSummary,
+	"type":        csvFieldType,
+	"issue type":  csvFieldType,
+	"description": csvFieldDescription,
+	"epic":        csvFieldEpic,
+	"epic link":   csvFieldEpic,
+	"labels":      csvFieldLabels,
+	"label":       csvFieldLabels,
+	"attachments": csvFieldAttachments,
+}
+
+// csvImportFileParser parses CSV files with a header row and an issue in
+// each of the other rows. Descriptions are markdown. Labels and attachments
+// are comma-separated and can be spread over more than one column, as in
+// Jira exports. Custom columns, whose headers are not mapped to a field,
+// are added to the end of the descriptions as a table.
+type csvImportFileParser struct {
+	// columns maps the lowercase headers of the columns to their fields
+	columns map[string]string
+
+	options markdown.ParserOptions
+}
+
+func newCSVImportFileParser(
+	columns map[string]string, options markdown.ParserOptions,
+) (*csvImportFileParser, error) {
+	fields := map[string]bool{}
+	for _, field := range csvHeaderFields {
+		fields[field] = true
+	}
+
+	p := &csvImportFileParser{
+		columns: map[string]string{},
+		options: options,
+	}
+	for header, field := range columns {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !fields[field] {
+			return nil, fmt.Errorf(
+				"Unknown field '%s' for column '%s'", field, header,
+			)
+		}
+		p.columns[strings.ToLower(strings.TrimSpace(header))] = field
+	}
+	return p, nil
+}
+
+// columnField returns the field of the column with the header, or "" for
+// custom columns.
+func (p *csvImportFileParser) columnField(header string) string {
+	header = strings.ToLower(strings.TrimSpace(header))
+	if field, ok := p.columns[header]; ok {
+		return field
+	}
+	return csvHeaderFields[header]
+}
+
+func (p *csvImportFileParser) ParseImportFile(
+	importFile io.Reader,
+) ([]*domain.Issue, error) {
+	records, err := csv.NewReader(importFile).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read CSV file: %s", err)
+	}
+	if len(records) == 0 {
+		return []*domain.Issue{}, nil
+	}
+
+	// spreadsheets may start the file with a byte order mark
+	header := records[0]
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	fields := make([]string, len(header))
+	hasSummary := false
+	for i := range header {
+		fields[i] = p.columnField(header[i])
+		hasSummary = hasSummary || fields[i] == csvFieldSummary
+	}
+	if !hasSummary {
+		return nil, errors.New("CSV file has no summary column")
+	}
+
+	issues := []*domain.Issue{}
+	for i, record := range records[1:] {
+		if isBlankRecord(record) {
+			continue
+		}
+		issue, err := p.makeIssue(header, fields, record)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"Failed parsing row %d in CSV file: %s", i+2, err,
+			)
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *csvImportFileParser) makeIssue(
+	header []string, fields []string, record []string,
+) (*domain.Issue, error) {
+	issue := &domain.Issue{Type: domain.IssueTypeStory}
+	customColumns := []int{}
+	for i, value := range record {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		var err error
+		switch fields[i] {
+		case csvFieldSummary:
+			issue.Title = value
+		case csvFieldType:
+			issue.Type, err = markdown.ParseIssueType(
+				value, p.options.TypeAliases,
+			)
+		case csvFieldDescription:
+			issue.Description, err = markdown.ParseDocument(
+				[]byte(value), p.options,
+			)
+		case csvFieldEpic:
+			issue.Epic = &domain.Epic{ID: value}
+		case csvFieldLabels:
+			issue.Labels = append(issue.Labels, parseLabels(value)...)
+		case csvFieldAttachments:
+			for _, path := range strings.Split(value, ",") {
+				if path = strings.TrimSpace(path); path != "" {
+					issue.Attachments = append(issue.Attachments,
+						domain.Attachment{Path: path})
+				}
+			}
+		default:
+			customColumns = append(customColumns, i)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if issue.Title == "" {
+		return nil, errors.New("Issue has no summary")
+	}
+
+	if len(customColumns) != 0 {
+		if issue.Description == nil {
+			issue.Description = &domain.Document{}
+		}
+		table := issue.Description.AddTable()
+		for _, i := range customColumns {
+			row := table.AddRow()
+			row.AddCell().AddText(
+				strings.TrimSpace(header[i]), domain.TextMode{Bold: true},
+			)
+			row.AddCell().AddText(
+				strings.TrimSpace(record[i]), domain.TextMode{},
+			)
+		}
+	}
+
+	return issue, nil
+}
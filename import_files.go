@@ -0,0 +1,245 @@
+package issuez
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/glestaris/issuez/domain"
+)
+
+// StdinPath is the import path that reads the import file from the standard
+// input.
+const StdinPath = "-"
+
+func isStdinPath(path string) bool {
+	return path == StdinPath
+}
+
+// ImportFileName returns the name an import path is reported under.
+func ImportFileName(path string) string {
+	if isStdinPath(path) {
+		return "<stdin>"
+	}
+	return path
+}
+
+// readImportFile reads the contents of the file at an import path.
+func readImportFile(path string) ([]byte, error) {
+	if isStdinPath(path) {
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read standard input: %s", err)
+		}
+		return data, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read import file: %s", err)
+	}
+	return data, nil
+}
+
+// LoadImportFile reads the import file at the path and expands its include
+// directives, if it is a markdown file. Unless the vars of the options are
+// nil, the file is then rendered as a template with them.
+func LoadImportFile(
+	importFilePath string, options ImportFileOptions,
+) ([]byte, error) {
+	data, err := readImportFile(importFilePath)
+	if err != nil {
+		return nil, err
+	}
+	expanded, err := expandImportFile(importFilePath, data, options)
+	if err != nil {
+		return nil, err
+	}
+	return expanded.Data, nil
+}
+
+// expandImportFile expands the include directives and the template of the
+// data of the import file at the path. The lines of a rendered template have
+// no positions, as they do not match the lines of the files.
+func expandImportFile(
+	importFilePath string, data []byte, options ImportFileOptions,
+) (*ExpandedImportFile, error) {
+	var err error
+	name := ImportFileName(importFilePath)
+	expanded := &ExpandedImportFile{Data: data}
+	if options.format(importFilePath) == ImportFormatMarkdown {
+		expanded, err = ExpandIncludes(importFilePath, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if options.Vars == nil {
+		return expanded, nil
+	}
+
+	data, err = RenderImportTemplate(name, expanded.Data, options.Vars)
+	if err != nil {
+		return nil, expanded.LocateTemplateError(name, err)
+	}
+	return &ExpandedImportFile{Data: data}, nil
+}
+
+// ParseImportFilePath loads and parses the import file at the path with the
+// parser of its format. The local paths of the issues are resolved by
+// ResolveLocalPaths.
+func ParseImportFilePath(
+	importFilePath string, options ImportFileOptions,
+) ([]*domain.Issue, error) {
+	data, err := readImportFile(importFilePath)
+	if err != nil {
+		return nil, err
+	}
+	expanded, err := expandImportFile(importFilePath, data, options)
+	if err != nil {
+		return nil, err
+	}
+	return parseImportData(importFilePath, expanded, options)
+}
+
+// parseImportData parses the expanded data of the import file at the path.
+func parseImportData(
+	importFilePath string, expanded *ExpandedImportFile,
+	options ImportFileOptions,
+) ([]*domain.Issue, error) {
+	parser, err := NewImportFileParser(
+		options.format(importFilePath), options,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := parser.ParseImportFile(bytes.NewReader(expanded.Data))
+	if err != nil {
+		return nil, expanded.LocateIssueError(err)
+	}
+	if !isStdinPath(importFilePath) {
+		ResolveLocalPaths(issues, importFilePath)
+	}
+	return issues, nil
+}
+
+// isImportFile checks whether a file found in a directory is imported. Unless
+// the format of the files is set, only files of the extensions of the import
+// formats are.
+func isImportFile(path string, format string) bool {
+	if format != "" {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	_, ok := importFormatExtensions[ext]
+	return ok
+}
+
+// FindImportFiles expands the import command arguments to a list of file
+// paths. Arguments can be files, glob patterns or, when recursive is set,
+// directories which are searched for the files of the import formats, or for
+// any file when the format of the files is set. The "-" argument stands for
+// the standard input and is passed through as is. Each file is returned
+// once, in the order it was first found.
+func FindImportFiles(
+	args []string, recursive bool, format string,
+) ([]string, error) {
+	paths := []string{}
+	seen := map[string]bool{}
+	addPath := func(path string) {
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+
+	for _, arg := range args {
+		if isStdinPath(arg) {
+			addPath(arg)
+			continue
+		}
+
+		matches := []string{arg}
+		if strings.ContainsAny(arg, "*?[") {
+			var err error
+			matches, err = filepath.Glob(arg)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid pattern '%s': %s", arg, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("No files match pattern '%s'", arg)
+			}
+			sort.Strings(matches)
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to open '%s': %s", match, err)
+			}
+			if !info.IsDir() {
+				addPath(match)
+				continue
+			}
+
+			if !recursive {
+				return nil, fmt.Errorf(
+					"'%s' is a directory (use --recursive to import it)",
+					match,
+				)
+			}
+			err = filepath.Walk(match, func(
+				path string, info os.FileInfo, err error,
+			) error {
+				if err != nil {
+					return err
+				}
+				if !info.IsDir() && isImportFile(path, format) {
+					addPath(path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf(
+					"Failed to search directory '%s': %s", match, err,
+				)
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+// ResolveLocalPaths makes the relative paths of the local images and the
+// attachments of the issues, which are relative to the markdown file the
+// issues were found in, relative to the working directory instead. The paths
+// found in included files are relative to that markdown file too, as the
+// issues do not know the files their lines come from.
+func ResolveLocalPaths(issues []*domain.Issue, markdownFilePath string) {
+	dir := filepath.Dir(markdownFilePath)
+	resolve := func(path string) string {
+		if filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(dir, path)
+	}
+
+	for _, issue := range issues {
+		for i := range issue.Attachments {
+			issue.Attachments[i].Path = resolve(issue.Attachments[i].Path)
+		}
+		if issue.Description == nil {
+			continue
+		}
+		for _, image := range issue.Description.Images() {
+			if image.IsLocal() {
+				image.Source = resolve(image.Source)
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package issuez_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glestaris/issuez"
+	"github.com/glestaris/issuez/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func makeImportFilesTree(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "issuez-import-files")
+	require.NoError(t, err)
+
+	files := []string{
+		"a.md",
+		"b.md",
+		"notes.txt",
+		"epics/c.md",
+		"epics/nested/d.markdown",
+		"epics/tasks.csv",
+		"epics/nested/e.yml",
+	}
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte("Title"), 0644))
+	}
+
+	return dir
+}
+
+func TestFindImportFilesFiles(t *testing.T) {
+	dir := makeImportFilesTree(t)
+	defer os.RemoveAll(dir)
+
+	paths, err := issuez.FindImportFiles([]string{
+		filepath.Join(dir, "b.md"),
+		filepath.Join(dir, "a.md"),
+		filepath.Join(dir, "b.md"),
+	}, false, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "b.md"),
+		filepath.Join(dir, "a.md"),
+	}, paths)
+}
+
+func TestFindImportFilesGlob(t *testing.T) {
+	dir := makeImportFilesTree(t)
+	defer os.RemoveAll(dir)
+
+	paths, err := issuez.FindImportFiles([]string{
+		filepath.Join(dir, "*.md"),
+	}, false, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "a.md"),
+		filepath.Join(dir, "b.md"),
+	}, paths)
+
+	_, err = issuez.FindImportFiles([]string{
+		filepath.Join(dir, "*.yaml"),
+	}, false, "")
+	require.Error(t, err)
+}
+
+func TestFindImportFilesDirectory(t *testing.T) {
+	dir := makeImportFilesTree(t)
+	defer os.RemoveAll(dir)
+
+	_, err := issuez.FindImportFiles([]string{dir}, false, "")
+	require.Error(t, err)
+
+	paths, err := issuez.FindImportFiles([]string{dir}, true, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "a.md"),
+		filepath.Join(dir, "b.md"),
+		filepath.Join(dir, "epics/c.md"),
+		filepath.Join(dir, "epics/nested/d.markdown"),
+		filepath.Join(dir, "epics/nested/e.yml"),
+		filepath.Join(dir, "epics/tasks.csv"),
+	}, paths)
+
+	paths, err = issuez.FindImportFiles([]string{dir}, true, "markdown")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "a.md"),
+		filepath.Join(dir, "b.md"),
+		filepath.Join(dir, "epics/c.md"),
+		filepath.Join(dir, "epics/nested/d.markdown"),
+		filepath.Join(dir, "epics/nested/e.yml"),
+		filepath.Join(dir, "epics/tasks.csv"),
+		filepath.Join(dir, "notes.txt"),
+	}, paths)
+}
+
+func TestFindImportFilesMissing(t *testing.T) {
+	_, err := issuez.FindImportFiles([]string{"/does/not/exist.md"}, false, "")
+	require.Error(t, err)
+}
+
+func TestFindImportFilesStdin(t *testing.T) {
+	dir := makeImportFilesTree(t)
+	defer os.RemoveAll(dir)
+
+	paths, err := issuez.FindImportFiles([]string{
+		"-", filepath.Join(dir, "a.md"), "-",
+	}, false, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"-", filepath.Join(dir, "a.md")}, paths)
+}
+
+func TestResolveLocalPaths(t *testing.T) {
+	description := &domain.Document{}
+	description.AddImage("img/bug.png", "")
+	description.AddImage("/tmp/abs.png", "")
+	description.AddImage("https://example.com/remote.png", "")
+	description.AddUnorderedList().AddItem().AddImage("./nested.png", "")
+	issues := []*domain.Issue{
+		{Title: "With images", Description: description},
+		{
+			Title: "Without description",
+			Attachments: []domain.Attachment{
+				{Path: "logs/crash.log"},
+				{Path: "/tmp/trace.har"},
+			},
+		},
+	}
+
+	issuez.ResolveLocalPaths(issues, filepath.Join("planning", "bugs.md"))
+
+	images := description.Images()
+	require.Len(t, images, 4)
+	require.Equal(t, filepath.Join("planning", "img", "bug.png"), images[0].Source)
+	require.Equal(t, "/tmp/abs.png", images[1].Source)
+	require.Equal(t, "https://example.com/remote.png", images[2].Source)
+	require.Equal(t, filepath.Join("planning", "nested.png"), images[3].Source)
+	require.Equal(t, []domain.Attachment{
+		{Path: filepath.Join("planning", "logs", "crash.log")},
+		{Path: "/tmp/trace.har"},
+	}, issues[1].Attachments)
+}
@@ -0,0 +1,109 @@
+package issuez
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/markdown"
+)
+
+// ImportFileOptions configures how import files are loaded and parsed.
+type ImportFileOptions struct {
+	// Format is the format of the import files. When it is empty, the
+	// format of a file is found by its extension.
+	Format string
+
+	// Markdown configures the markdown parser, which also parses the
+	// descriptions and the issue types of the other formats.
+	Markdown markdown.ParserOptions
+
+	// Columns maps the headers of the columns of CSV files to the fields
+	// of the issues they hold, e.g. "Ticket" to "summary". Columns are
+	// otherwise mapped by the name of their field.
+	Columns map[string]string
+
+	// Vars are the variables of the import files, which are rendered as
+	// templates before they are parsed, unless the vars are nil.
+	Vars map[string]string
+}
+
+// format returns the format of the import file at the path.
+func (o ImportFileOptions) format(path string) string {
+	if o.Format == "" {
+		return ImportFileFormat(path)
+	}
+	return o.Format
+}
+
+// ImportFileParser parses the issues of an import file of some format.
+type ImportFileParser interface {
+	ParseImportFile(importFile io.Reader) ([]*domain.Issue, error)
+}
+
+// The formats of import files
+const (
+	ImportFormatMarkdown = "markdown"
+	ImportFormatCSV      = "csv"
+	ImportFormatYAML     = "yaml"
+)
+
+var importFormatExtensions = map[string]string{
+	".md":       ImportFormatMarkdown,
+	".markdown": ImportFormatMarkdown,
+	".csv":      ImportFormatCSV,
+	".yaml":     ImportFormatYAML,
+	".yml":      ImportFormatYAML,
+}
+
+// ImportFileFormat returns the format of the import file at the path, found
+// by its extension. Files of unknown extensions, and the standard input,
+// are markdown files.
+func ImportFileFormat(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if format, ok := importFormatExtensions[ext]; ok {
+		return format
+	}
+	return ImportFormatMarkdown
+}
+
+// NewImportFileParser returns the parser of the import files of the format.
+func NewImportFileParser(
+	format string, options ImportFileOptions,
+) (ImportFileParser, error) {
+	switch format {
+	case ImportFormatMarkdown:
+		return &markdownImportFileParser{options: options}, nil
+	case ImportFormatCSV:
+		return newCSVImportFileParser(options.Columns, options.Markdown)
+	case ImportFormatYAML:
+		return &yamlImportFileParser{options: options.Markdown}, nil
+	default:
+		return nil, fmt.Errorf("Unknown import file format '%s'", format)
+	}
+}
+
+type markdownImportFileParser struct {
+	options ImportFileOptions
+}
+
+func (p *markdownImportFileParser) ParseImportFile(
+	importFile io.Reader,
+) ([]*domain.Issue, error) {
+	return markdown.ParseImportFileWithOptions(
+		importFile, p.options.Markdown,
+	)
+}
+
+// parseLabels parses a comma-separated list of labels.
+func parseLabels(labels string) []domain.Label {
+	domainLabels := []domain.Label{}
+	for _, label := range strings.Split(labels, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			domainLabels = append(domainLabels, domain.Label{Label: label})
+		}
+	}
+	return domainLabels
+}
@@ -0,0 +1,163 @@
+package issuez_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/glestaris/issuez"
+	"github.com/glestaris/issuez/domain"
+	"github.com/stretchr/testify/require"
+)
+
+/******************************************************************************
+ * Formats
+ *****************************************************************************/
+
+func TestImportFileFormat(t *testing.T) {
+	require.Equal(t, issuez.ImportFormatMarkdown, issuez.ImportFileFormat("a.md"))
+	require.Equal(t, issuez.ImportFormatMarkdown, issuez.ImportFileFormat("a"))
+	require.Equal(t, issuez.ImportFormatMarkdown, issuez.ImportFileFormat("-"))
+	require.Equal(t, issuez.ImportFormatCSV, issuez.ImportFileFormat("a.CSV"))
+	require.Equal(t, issuez.ImportFormatYAML, issuez.ImportFileFormat("a.yml"))
+	require.Equal(t, issuez.ImportFormatYAML, issuez.ImportFileFormat("a.yaml"))
+}
+
+func TestNewImportFileParserUnknownFormat(t *testing.T) {
+	_, err := issuez.NewImportFileParser("xml", issuez.ImportFileOptions{})
+	require.EqualError(t, err, "Unknown import file format 'xml'")
+}
+
+func parseImportFileFormat(
+	t *testing.T, format string, options issuez.ImportFileOptions,
+	importFile string,
+) ([]*domain.Issue, error) {
+	parser, err := issuez.NewImportFileParser(format, options)
+	require.NoError(t, err)
+	return parser.ParseImportFile(strings.NewReader(importFile))
+}
+
+/******************************************************************************
+ * CSV
+ *****************************************************************************/
+
+func TestCSVParser(t *testing.T) {
+	csv := "\ufeffSummary,Issue Type,Description,Epic Link,Labels,Labels\n" +
+		"First,Bug,\"Some **bold** text.\",EP-1,\"a, b\",c\n" +
+		",,,,,\n" +
+		"Second,,,,,\n"
+	issues, err := parseImportFileFormat(
+		t, issuez.ImportFormatCSV, issuez.ImportFileOptions{}, csv,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 2)
+	require.Equal(t, "First", issues[0].Title)
+	require.Equal(t, domain.IssueTypeBug, issues[0].Type)
+	require.Equal(t, "EP-1", issues[0].Epic.ID)
+	require.Equal(t, []domain.Label{
+		{Label: "a"}, {Label: "b"}, {Label: "c"},
+	}, issues[0].Labels)
+	require.NotNil(t, issues[0].Description)
+	require.Len(t, issues[0].Description.Nodes, 1)
+
+	require.Equal(t, "Second", issues[1].Title)
+	require.Equal(t, domain.IssueTypeStory, issues[1].Type)
+	require.Nil(t, issues[1].Epic)
+	require.Nil(t, issues[1].Description)
+}
+
+func TestCSVParserCustomColumns(t *testing.T) {
+	csv := "Name,Priority,Component\n" +
+		"First,High,\n"
+	issues, err := parseImportFileFormat(
+		t, issuez.ImportFormatCSV, issuez.ImportFileOptions{
+			Columns: map[string]string{"name": "Summary"},
+		}, csv,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+	require.Equal(t, "First", issues[0].Title)
+	require.Len(t, issues[0].Description.Nodes, 1)
+	table := issues[0].Description.Nodes[0]
+	require.Equal(t, domain.DocumentNodeTypeTable, table.Type)
+	require.Len(t, table.Rows, 1)
+}
+
+func TestCSVParserErrors(t *testing.T) {
+	_, err := issuez.NewImportFileParser(issuez.ImportFormatCSV,
+		issuez.ImportFileOptions{
+			Columns: map[string]string{"Name": "owner"},
+		})
+	require.EqualError(t, err, "Unknown field 'owner' for column 'Name'")
+
+	_, err = parseImportFileFormat(
+		t, issuez.ImportFormatCSV, issuez.ImportFileOptions{}, "Name\nFirst\n",
+	)
+	require.EqualError(t, err, "CSV file has no summary column")
+
+	_, err = parseImportFileFormat(
+		t, issuez.ImportFormatCSV, issuez.ImportFileOptions{},
+		"Summary,Type\nFirst,Bug\nSecond,Epic\n",
+	)
+	require.EqualError(
+		t, err, "Failed parsing row 3 in CSV file: Unknown issue type Epic",
+	)
+}
+
+/******************************************************************************
+ * YAML
+ *****************************************************************************/
+
+func TestYAMLParser(t *testing.T) {
+	yaml := `
+- summary: First
+  type: bug
+  description: |
+    Some **bold** text.
+
+    - A
+    - B
+  epic: EP-1
+  labels: [a, "b, c"]
+  attachments: [screenshot.png]
+- summary: Second
+`
+	issues, err := parseImportFileFormat(
+		t, issuez.ImportFormatYAML, issuez.ImportFileOptions{}, yaml,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 2)
+	require.Equal(t, "First", issues[0].Title)
+	require.Equal(t, domain.IssueTypeBug, issues[0].Type)
+	require.Equal(t, "EP-1", issues[0].Epic.ID)
+	require.Equal(t, []domain.Label{
+		{Label: "a"}, {Label: "b"}, {Label: "c"},
+	}, issues[0].Labels)
+	require.Equal(t, []domain.Attachment{
+		{Path: "screenshot.png"},
+	}, issues[0].Attachments)
+	require.Len(t, issues[0].Description.Nodes, 2)
+
+	require.Equal(t, "Second", issues[1].Title)
+	require.Equal(t, domain.IssueTypeStory, issues[1].Type)
+	require.Nil(t, issues[1].Description)
+}
+
+func TestYAMLParserErrors(t *testing.T) {
+	_, err := parseImportFileFormat(
+		t, issuez.ImportFormatYAML, issuez.ImportFileOptions{},
+		"- summary: First\n  owner: me\n",
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Failed to parse YAML file")
+
+	_, err = parseImportFileFormat(
+		t, issuez.ImportFormatYAML, issuez.ImportFileOptions{},
+		"- summary: First\n- type: bug\n",
+	)
+	require.EqualError(
+		t, err, "Failed parsing issue 2 in YAML file: Issue has no summary",
+	)
+}
@@ -0,0 +1,148 @@
+package issuez
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/glestaris/issuez/markdown"
+)
+
+// includeRe matches the lines made of an include directive, such as
+// "<!-- include: ./snippets/dod.md -->".
+var includeRe = regexp.MustCompile(`^\s*<!--\s*include:\s*(.*?)\s*-->\s*$`)
+
+// SourcePosition is the line of an import file, or of a file it includes,
+// a line of an expanded import file comes from.
+type SourcePosition struct {
+	Path string
+	Line int
+}
+
+func (p SourcePosition) String() string {
+	return fmt.Sprintf("%s:%d", p.Path, p.Line)
+}
+
+// ExpandedImportFile is an import file whose include directives were
+// replaced by the files they include.
+type ExpandedImportFile struct {
+	Data []byte
+
+	// Positions are the positions of the lines of the data
+	Positions []SourcePosition
+}
+
+// Position returns the position of the line, counted from 1, of the data.
+func (f *ExpandedImportFile) Position(line int) SourcePosition {
+	if line < 1 || line > len(f.Positions) {
+		return SourcePosition{}
+	}
+	return f.Positions[line-1]
+}
+
+// LocateTemplateError points the lines of the data the error of the template
+// with the name mentions at the lines of the files they come from.
+func (f *ExpandedImportFile) LocateTemplateError(
+	name string, err error,
+) error {
+	lineRe := regexp.MustCompile(
+		`template: ` + regexp.QuoteMeta(name) + `:(\d+)`,
+	)
+	msg := lineRe.ReplaceAllStringFunc(err.Error(), func(
+		match string,
+	) string {
+		line, _ := strconv.Atoi(lineRe.FindStringSubmatch(match)[1])
+		position := f.Position(line)
+		if position.Path == "" {
+			return match
+		}
+		return "template: " + position.String()
+	})
+	return errors.New(msg)
+}
+
+// LocateIssueError points the line of the data the markdown issue of the
+// error starts at to the line of the file it comes from.
+func (f *ExpandedImportFile) LocateIssueError(err error) error {
+	issueErr, ok := err.(*markdown.IssueError)
+	if !ok {
+		return err
+	}
+	position := f.Position(issueErr.Line)
+	if position.Path == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %s", position, err)
+}
+
+// ExpandIncludes replaces the include directives of the markdown import file
+// at the path, whose contents are the data, by the files they include. The
+// paths of the directives are relative to the file they are found in, and
+// included files can include other files, as long as they do not include
+// themselves. Directives in fenced code blocks are left as they are.
+func ExpandIncludes(path string, data []byte) (*ExpandedImportFile, error) {
+	f := &ExpandedImportFile{Data: []byte{}, Positions: []SourcePosition{}}
+	if err := f.expand(path, data, []string{}); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *ExpandedImportFile) expand(
+	path string, data []byte, including []string,
+) error {
+	name := ImportFileName(path)
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("Failed to resolve '%s': %s", name, err)
+	}
+	for _, includingPath := range including {
+		if includingPath == absPath {
+			return fmt.Errorf("Include cycle at '%s'", name)
+		}
+	}
+	if !isStdinPath(path) {
+		including = append(including, absPath)
+	}
+
+	fence := markdown.CodeFence{}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	for i, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		position := SourcePosition{Path: name, Line: i + 1}
+		matches := includeRe.FindSubmatch(line)
+		if fence.Next(string(line)) || matches == nil {
+			f.Data = append(f.Data, line...)
+			f.Positions = append(f.Positions, position)
+			continue
+		}
+
+		includedPath := string(matches[1])
+		if !filepath.IsAbs(includedPath) && !isStdinPath(path) {
+			includedPath = filepath.Join(filepath.Dir(path), includedPath)
+		}
+		includedData, err := ioutil.ReadFile(includedPath)
+		if err != nil {
+			return fmt.Errorf(
+				"%s: Failed to include '%s': %s", position, matches[1], err,
+			)
+		}
+		// the line after the directive does not join the last included line
+		newline := []byte("\n")
+		if len(includedData) != 0 && !bytes.HasSuffix(includedData, newline) {
+			includedData = append(includedData, newline...)
+		}
+		err = f.expand(includedPath, includedData, including)
+		if err != nil {
+			return fmt.Errorf("%s: %s", position, err)
+		}
+	}
+
+	return nil
+}
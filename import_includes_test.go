@@ -0,0 +1,129 @@
+package issuez_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glestaris/issuez"
+	"github.com/stretchr/testify/require"
+)
+
+func makeIncludesTree(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "issuez-includes")
+	require.NoError(t, err)
+
+	for file, contents := range files {
+		path := filepath.Join(dir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0644))
+	}
+
+	return dir
+}
+
+func TestExpandIncludes(t *testing.T) {
+	dir := makeIncludesTree(t, map[string]string{
+		"snippets/dod.md":    "- [ ] Tested\n<!-- include: review.md -->",
+		"snippets/review.md": "- [ ] Reviewed\n",
+	})
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "issues.md")
+	f, err := issuez.ExpandIncludes(path, []byte(`[Chore] Title
+
+<!--   include: ./snippets/dod.md -->
+
+`+"```"+`
+<!-- include: ./snippets/dod.md -->
+`+"```"+`
+`))
+	require.NoError(t, err)
+
+	require.Equal(t, `[Chore] Title
+
+- [ ] Tested
+- [ ] Reviewed
+
+`+"```"+`
+<!-- include: ./snippets/dod.md -->
+`+"```"+`
+`, string(f.Data))
+
+	dod := filepath.Join(dir, "snippets", "dod.md")
+	review := filepath.Join(dir, "snippets", "review.md")
+	require.Equal(t, []issuez.SourcePosition{
+		{Path: path, Line: 1},
+		{Path: path, Line: 2},
+		{Path: dod, Line: 1},
+		{Path: review, Line: 1},
+		{Path: path, Line: 4},
+		{Path: path, Line: 5},
+		{Path: path, Line: 6},
+		{Path: path, Line: 7},
+	}, f.Positions)
+}
+
+func TestExpandIncludesMissingFile(t *testing.T) {
+	dir := makeIncludesTree(t, map[string]string{
+		"snippets/dod.md": "- [ ] Tested\n<!-- include: missing.md -->\n",
+	})
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "issues.md")
+	_, err := issuez.ExpandIncludes(
+		path, []byte("Title\n<!-- include: snippets/dod.md -->\n"),
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), path+":2: "+
+		filepath.Join(dir, "snippets", "dod.md")+":2: "+
+		"Failed to include 'missing.md'")
+}
+
+func TestExpandIncludesCycle(t *testing.T) {
+	dir := makeIncludesTree(t, map[string]string{
+		"a.md": "<!-- include: b.md -->\n",
+		"b.md": "<!-- include: a.md -->\n",
+	})
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "issues.md")
+	_, err := issuez.ExpandIncludes(path, []byte("<!-- include: a.md -->\n"))
+	a := filepath.Join(dir, "a.md")
+	require.EqualError(t, err, path+":1: "+a+":1: "+
+		filepath.Join(dir, "b.md")+":1: Include cycle at '"+a+"'")
+}
+
+func TestExpandedImportFileLocateTemplateError(t *testing.T) {
+	f := &issuez.ExpandedImportFile{Positions: []issuez.SourcePosition{
+		{Path: "issues.md", Line: 1},
+		{Path: "snippets/dod.md", Line: 3},
+	}}
+
+	err := f.LocateTemplateError("issues.md", errors.New(
+		`template: issues.md:2:5: executing "issues.md" at <.v>: error`,
+	))
+	require.EqualError(
+		t, err,
+		`template: snippets/dod.md:3:5: executing "issues.md" at <.v>: error`,
+	)
+}
+
+func TestParseImportFilePathIncludedIssueError(t *testing.T) {
+	issues := "[Chore] A\n\n---\n\n<!-- include: snippets/b.md -->\n"
+	dir := makeIncludesTree(t, map[string]string{
+		"issues.md":     issues,
+		"snippets/b.md": "[Bug] B\n\n---\n\n[Spike] C\n",
+	})
+	defer os.RemoveAll(dir)
+
+	_, err := issuez.ParseImportFilePath(
+		filepath.Join(dir, "issues.md"), issuez.ImportFileOptions{},
+	)
+	require.EqualError(
+		t, err, filepath.Join(dir, "snippets", "b.md")+":5: "+
+			"Failed parsing issue 3 in markdown file: Unknown issue type Spike",
+	)
+}
@@ -0,0 +1,114 @@
+package issuez
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+// frontMatterDelimiter is the line opening and closing the YAML front matter
+// of import templates.
+const frontMatterDelimiter = "---"
+
+// templateFuncs are the functions available to import templates on top of
+// the text/template builtins.
+var templateFuncs = template.FuncMap{
+	// split turns a comma-separated variable into a list to range over
+	"split": func(s string, sep string) []string {
+		items := []string{}
+		for _, item := range strings.Split(s, sep) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	},
+}
+
+// RenderImportTemplate renders an import file as a text/template, before it
+// is parsed. The variables of the template are the ones set in the YAML front
+// matter of the file, which is not part of the output, and the vars, which
+// take precedence. A block between "---" lines at the start of the file is
+// front matter only if it is a YAML mapping, since legacy import files start
+// with the separator of their issues. Errors are reported with the name and the line of the
+// file they occurred in.
+func RenderImportTemplate(
+	name string, data []byte, vars map[string]string,
+) ([]byte, error) {
+	values, frontMatterLines, body := splitFrontMatter(data)
+	for name, value := range vars {
+		values[name] = value
+	}
+
+	tmpl, err := template.New(name).
+		Funcs(templateFuncs).
+		Option("missingkey=error").
+		Parse(string(body))
+	if err != nil {
+		err = shiftTemplateError(name, err, frontMatterLines)
+		return nil, fmt.Errorf("Failed to parse template: %s", err)
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, values); err != nil {
+		err = shiftTemplateError(name, err, frontMatterLines)
+		return nil, fmt.Errorf("Failed to render template: %s", err)
+	}
+	return out.Bytes(), nil
+}
+
+// shiftTemplateError adds the lines of the front matter to the lines of the
+// body the error of the template with the name mentions, so that they point
+// at the lines of the file.
+func shiftTemplateError(name string, err error, lines int) error {
+	if lines == 0 {
+		return err
+	}
+	lineRe := regexp.MustCompile(
+		`template: ` + regexp.QuoteMeta(name) + `:(\d+)`,
+	)
+	msg := lineRe.ReplaceAllStringFunc(err.Error(), func(
+		match string,
+	) string {
+		line, _ := strconv.Atoi(lineRe.FindStringSubmatch(match)[1])
+		return fmt.Sprintf("template: %s:%d", name, line+lines)
+	})
+	return errors.New(msg)
+}
+
+// splitFrontMatter splits the YAML front matter, found between two "---"
+// lines at the very start of the data, from the rest of the data and returns
+// its values. The number of lines of the front matter, delimiters included,
+// is returned too. Unless the lines between the delimiters are a YAML
+// mapping, e.g. the first issue of a legacy import file, there is no front
+// matter and the data is returned as is.
+func splitFrontMatter(data []byte) (map[string]interface{}, int, []byte) {
+	values := map[string]interface{}{}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	if !isFrontMatterDelimiter(lines[0]) {
+		return values, 0, data
+	}
+
+	offset := len(lines[0])
+	for i, line := range lines[1:] {
+		if isFrontMatterDelimiter(line) {
+			frontMatter := data[len(lines[0]):offset]
+			if err := yaml.Unmarshal(frontMatter, &values); err != nil {
+				return map[string]interface{}{}, 0, data
+			}
+			return values, i + 2, data[offset+len(line):]
+		}
+		offset += len(line)
+	}
+	return values, 0, data
+}
+
+func isFrontMatterDelimiter(line []byte) bool {
+	return string(bytes.TrimRight(line, "\r\n")) == frontMatterDelimiter
+}
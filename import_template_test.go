@@ -0,0 +1,119 @@
+package issuez_test
+
+import (
+	"testing"
+
+	"github.com/glestaris/issuez"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderImportTemplateVars(t *testing.T) {
+	out, err := issuez.RenderImportTemplate(
+		"issues.md", []byte("[Chore] Release {{ .version }}\n"),
+		map[string]string{"version": "2.4"},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "[Chore] Release 2.4\n", string(out))
+}
+
+func TestRenderImportTemplateFrontMatter(t *testing.T) {
+	template := `---
+services: [api, web]
+sprint: 41
+---
+{{ range .services }}[Chore] Deploy {{ . }} in sprint {{ $.sprint }}
+---
+{{ end }}`
+	out, err := issuez.RenderImportTemplate(
+		"issues.md", []byte(template), map[string]string{"sprint": "42"},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, `[Chore] Deploy api in sprint 42
+---
+[Chore] Deploy web in sprint 42
+---
+`, string(out))
+}
+
+func TestRenderImportTemplateFrontMatterTrim(t *testing.T) {
+	template := `---
+services: [api, web]
+---
+{{- range .services }}
+[Chore] Deploy {{ . }}
+{{- end }}
+`
+	out, err := issuez.RenderImportTemplate(
+		"issues.md", []byte(template), map[string]string{},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "\n[Chore] Deploy api\n[Chore] Deploy web\n", string(out))
+
+	out, err = issuez.RenderImportTemplate(
+		"issues.md", []byte("---\nsprint: 41\nservice: api\n---\nA"),
+		map[string]string{},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "A", string(out))
+}
+
+func TestRenderImportTemplateSplit(t *testing.T) {
+	out, err := issuez.RenderImportTemplate(
+		"issues.md",
+		[]byte(`{{ range split .services "," }}{{ . }};{{ end }}`),
+		map[string]string{"services": "api, web,"},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "api;web;", string(out))
+}
+
+func TestRenderImportTemplateNoFrontMatter(t *testing.T) {
+	template := "---\n[Bug] Title\n"
+	out, err := issuez.RenderImportTemplate(
+		"issues.md", []byte(template), map[string]string{},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, template, string(out))
+
+	for _, template := range []string{
+		"---\n[FEATURE] Sign up\nL: auth\n---\n[BUG] Log in\n---\n",
+		"---\n[oops\n---\n",
+		"---\nTitle\n---\nText\n",
+	} {
+		out, err := issuez.RenderImportTemplate(
+			"issues.md", []byte(template), map[string]string{},
+		)
+		require.NoError(t, err)
+		require.Equal(t, template, string(out))
+	}
+}
+
+func TestRenderImportTemplateErrors(t *testing.T) {
+	_, err := issuez.RenderImportTemplate(
+		"issues.md", []byte("---\nsprint: 42\n---\n\n{{ if }}\n"),
+		map[string]string{},
+	)
+	require.EqualError(
+		t, err,
+		"Failed to parse template: template: issues.md:5: missing value for if",
+	)
+
+	_, err = issuez.RenderImportTemplate(
+		"issues.md", []byte("Title\n\n{{ .version }}\n"),
+		map[string]string{},
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "template: issues.md:3:")
+	require.Contains(t, err.Error(), `no entry for key "version"`)
+
+	_, err = issuez.RenderImportTemplate(
+		"issues.md", []byte("---\nsprint: 42\n---\nTitle\n{{ .v }}\n"),
+		map[string]string{},
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "template: issues.md:5:")
+}
@@ -0,0 +1,93 @@
+package issuez
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/markdown"
+	"gopkg.in/yaml.v2"
+)
+
+// yamlIssue is an issue of a YAML file. The description is markdown.
+type yamlIssue struct {
+	Summary     string   `yaml:"summary"`
+	Type        string   `yaml:"type"`
+	Description string   `yaml:"description"`
+	Epic        string   `yaml:"epic"`
+	Labels      []string `yaml:"labels"`
+	Attachments []string `yaml:"attachments"`
+}
+
+// yamlImportFileParser parses YAML files holding a list of issues.
+type yamlImportFileParser struct {
+	options markdown.ParserOptions
+}
+
+func (p *yamlImportFileParser) ParseImportFile(
+	importFile io.Reader,
+) ([]*domain.Issue, error) {
+	data, err := ioutil.ReadAll(importFile)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read YAML file: %s", err)
+	}
+
+	yamlIssues := []yamlIssue{}
+	if err := yaml.UnmarshalStrict(data, &yamlIssues); err != nil {
+		return nil, fmt.Errorf("Failed to parse YAML file: %s", err)
+	}
+
+	issues := make([]*domain.Issue, len(yamlIssues))
+	for i, yamlIssue := range yamlIssues {
+		issue, err := yamlIssue.makeIssue(p.options)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"Failed parsing issue %d in YAML file: %s", i+1, err,
+			)
+		}
+		issues[i] = issue
+	}
+
+	return issues, nil
+}
+
+func (y yamlIssue) makeIssue(
+	options markdown.ParserOptions,
+) (*domain.Issue, error) {
+	issue := &domain.Issue{Title: strings.TrimSpace(y.Summary)}
+	if issue.Title == "" {
+		return nil, errors.New("Issue has no summary")
+	}
+
+	var err error
+	issue.Type, err = markdown.ParseIssueType(y.Type, options.TypeAliases)
+	if err != nil {
+		return nil, err
+	}
+
+	issue.Description, err = markdown.ParseDocument(
+		[]byte(y.Description), options,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if epicID := strings.TrimSpace(y.Epic); epicID != "" {
+		issue.Epic = &domain.Epic{ID: epicID}
+	}
+
+	for _, label := range y.Labels {
+		issue.Labels = append(issue.Labels, parseLabels(label)...)
+	}
+
+	for _, path := range y.Attachments {
+		issue.Attachments = append(issue.Attachments, domain.Attachment{
+			Path: path,
+		})
+	}
+
+	return issue, nil
+}
@@ -0,0 +1,98 @@
+// Package issuez parses the issues of markdown, CSV and YAML import files and
+// imports them to issue trackers. It is the library behind the issuez
+// command, which tools can embed instead of running the command.
+//
+// The markdown import files are parsed by the markdown package and the
+// trackers are the services of the tracker package. Importer puts them
+// together:
+//
+//	trackerService, err := tracker.NewTrackerService(domain.Tracker{
+//		Type:   "jira",
+//		Config: map[string]string{...},
+//	})
+//	...
+//	importer := issuez.NewImporter(trackerService, issuez.ImportFileOptions{
+//		Vars: map[string]string{"version": "2.4"},
+//	})
+//	issues, err := importer.ImportFiles(ctx, "release.md")
+package issuez
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/tracker"
+)
+
+// Importer parses import files with the options it was made with and
+// imports their issues to a tracker.
+type Importer struct {
+	trackerService tracker.TrackerService
+	options        ImportFileOptions
+}
+
+// NewImporter returns an importer of the issues of the import files parsed
+// with the options to the tracker service.
+func NewImporter(
+	trackerService tracker.TrackerService, options ImportFileOptions,
+) *Importer {
+	return &Importer{
+		trackerService: trackerService,
+		options:        options,
+	}
+}
+
+// Parse parses the issues of the import file read from the reader, which is
+// handled as the standard input: its format is the format of the options or
+// markdown, and its include directives are relative to the working
+// directory.
+func (i *Importer) Parse(importFile io.Reader) ([]*domain.Issue, error) {
+	data, err := ioutil.ReadAll(importFile)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read import file: %s", err)
+	}
+
+	expanded, err := expandImportFile(StdinPath, data, i.options)
+	if err != nil {
+		return nil, err
+	}
+	return parseImportData(StdinPath, expanded, i.options)
+}
+
+// ParseFile parses the issues of the import file at the path.
+func (i *Importer) ParseFile(path string) ([]*domain.Issue, error) {
+	return ParseImportFilePath(path, i.options)
+}
+
+// Import imports the issues to the tracker. The issues which are imported
+// are given the IDs of the tracker, even when the import fails or the
+// context is cancelled part of the way through.
+func (i *Importer) Import(
+	ctx context.Context, issues []*domain.Issue,
+) error {
+	return i.trackerService.ImportIssues(ctx, issues)
+}
+
+// ImportFiles parses the import files at the paths and imports their issues
+// to the tracker. Nothing is imported unless every file parses. When the
+// import fails, the issues are returned with the error, and the ones which
+// were imported have IDs.
+func (i *Importer) ImportFiles(
+	ctx context.Context, paths ...string,
+) ([]*domain.Issue, error) {
+	issues := []*domain.Issue{}
+	for _, path := range paths {
+		fileIssues, err := i.ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"Failed to parse '%s': %s", ImportFileName(path), err,
+			)
+		}
+		issues = append(issues, fileIssues...)
+	}
+
+	return issues, i.Import(ctx, issues)
+}
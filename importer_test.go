@@ -0,0 +1,112 @@
+package issuez_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/glestaris/issuez"
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/markdown"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTrackerService struct {
+	importedIssues []*domain.Issue
+}
+
+func (s *fakeTrackerService) ImportIssues(
+	ctx context.Context, issues []*domain.Issue,
+) error {
+	for i, issue := range issues {
+		issue.ID = "PROJ-" + strconv.Itoa(i+1)
+	}
+	s.importedIssues = append(s.importedIssues, issues...)
+	return nil
+}
+
+func (s *fakeTrackerService) ExportIssues(
+	ctx context.Context, query string,
+) ([]*domain.Issue, error) {
+	return nil, nil
+}
+
+func (s *fakeTrackerService) TestConnection(ctx context.Context) error {
+	return nil
+}
+
+func TestImporterParse(t *testing.T) {
+	importer := issuez.NewImporter(
+		&fakeTrackerService{}, issuez.ImportFileOptions{
+			Markdown: markdown.ParserOptions{
+				TypeAliases: map[string]domain.IssueType{
+					"spike": domain.IssueTypeChore,
+				},
+			},
+			Vars: map[string]string{"version": "2.4"},
+		},
+	)
+
+	issues, err := importer.Parse(strings.NewReader(
+		"[Spike] Look into {{ .version }}\n",
+	))
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+	require.Equal(t, domain.IssueTypeChore, issues[0].Type)
+	require.Equal(t, "Look into 2.4", issues[0].Title)
+}
+
+func TestImporterImportFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "issuez-importer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	mdPath := filepath.Join(dir, "issues.md")
+	require.NoError(t, ioutil.WriteFile(
+		mdPath, []byte("[Bug] First\n\n---\n\n[Chore] Second\n"), 0644,
+	))
+	csvPath := filepath.Join(dir, "issues.csv")
+	require.NoError(t, ioutil.WriteFile(
+		csvPath, []byte("Summary,Type\nThird,story\n"), 0644,
+	))
+
+	trackerService := &fakeTrackerService{}
+	importer := issuez.NewImporter(
+		trackerService, issuez.ImportFileOptions{},
+	)
+	issues, err := importer.ImportFiles(context.Background(), mdPath, csvPath)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 3)
+	require.Equal(t, issues, trackerService.importedIssues)
+	require.Equal(t, "First", issues[0].Title)
+	require.Equal(t, "Second", issues[1].Title)
+	require.Equal(t, "Third", issues[2].Title)
+	require.Equal(t, "PROJ-3", issues[2].ID)
+}
+
+func TestImporterImportFilesParseFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "issuez-importer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "issues.csv")
+	require.NoError(t, ioutil.WriteFile(
+		path, []byte("Name\nFirst\n"), 0644,
+	))
+
+	trackerService := &fakeTrackerService{}
+	importer := issuez.NewImporter(
+		trackerService, issuez.ImportFileOptions{},
+	)
+	_, err = importer.ImportFiles(context.Background(), path)
+	require.EqualError(
+		t, err, "Failed to parse '"+path+"': CSV file has no summary column",
+	)
+	require.Empty(t, trackerService.importedIssues)
+}
@@ -1,6 +1,8 @@
 package integration_test
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -11,7 +13,7 @@ func TestConnectionTest(t *testing.T) {
 	tc := newTestConfig(t)
 	jiraClient := newJiraClient(tc)
 
-	err := jiraClient.Test()
+	err := jiraClient.Test(context.Background())
 	require.NoError(t, err)
 }
 
@@ -20,7 +22,7 @@ func TestConnectionTestInvalidAuth(t *testing.T) {
 	tc.jiraAPIToken = "fail"
 	jiraClient := newJiraClient(tc)
 
-	err := jiraClient.Test()
+	err := jiraClient.Test(context.Background())
 	require.Error(t, err)
 }
 
@@ -28,7 +30,7 @@ func TestImportZeroIssues(t *testing.T) {
 	tc := newTestConfig(t)
 	jiraClient := newJiraClient(tc)
 
-	resp, err := jiraClient.ImportIssues([]*jira.Issue{})
+	resp, err := jiraClient.ImportIssues(context.Background(), []*jira.Issue{})
 	require.NoError(t, err)
 	require.Len(t, resp, 0)
 }
@@ -42,7 +44,7 @@ func TestImportOneIssue(t *testing.T) {
 	descriptionDoc.AddParagraph().
 		AddText("This is a paragraph", jira.ADFTextMode{})
 
-	resp, err := jiraClient.ImportIssues([]*jira.Issue{
+	resp, err := jiraClient.ImportIssues(context.Background(), []*jira.Issue{
 		{
 			Type:        jira.IssueTypeStory,
 			Summary:     "Hello world",
@@ -74,7 +76,7 @@ func TestImportTask(t *testing.T) {
 	descriptionDoc.AddParagraph().
 		AddText("This is a task paragraph", jira.ADFTextMode{})
 
-	resp, err := jiraClient.ImportIssues([]*jira.Issue{
+	resp, err := jiraClient.ImportIssues(context.Background(), []*jira.Issue{
 		{
 			Type:        jira.IssueTypeTask,
 			Summary:     "Hello world of tasks",
@@ -106,7 +108,7 @@ func TestImportBug(t *testing.T) {
 	descriptionDoc.AddParagraph().
 		AddText("This is a bug paragraph", jira.ADFTextMode{})
 
-	resp, err := jiraClient.ImportIssues([]*jira.Issue{
+	resp, err := jiraClient.ImportIssues(context.Background(), []*jira.Issue{
 		{
 			Type:        jira.IssueTypeBug,
 			Summary:     "Hello world of bugs",
@@ -142,7 +144,7 @@ func TestImportManyIssues(t *testing.T) {
 	descriptionBDoc.AddParagraph().
 		AddText("This is another paragraph", jira.ADFTextMode{})
 
-	resp, err := jiraClient.ImportIssues([]*jira.Issue{
+	resp, err := jiraClient.ImportIssues(context.Background(), []*jira.Issue{
 		{
 			Type:        jira.IssueTypeStory,
 			Summary:     "Hello world A",
@@ -186,7 +188,7 @@ func TestImportNoEpic(t *testing.T) {
 	descriptionDoc.AddParagraph().
 		AddText("This is a paragraph", jira.ADFTextMode{})
 
-	resp, err := jiraClient.ImportIssues([]*jira.Issue{
+	resp, err := jiraClient.ImportIssues(context.Background(), []*jira.Issue{
 		{
 			Type:        jira.IssueTypeStory,
 			Summary:     "Hello world",
@@ -213,7 +215,7 @@ func TestImportNoLabels(t *testing.T) {
 	descriptionDoc.AddParagraph().
 		AddText("This is a paragraph", jira.ADFTextMode{})
 
-	resp, err := jiraClient.ImportIssues([]*jira.Issue{
+	resp, err := jiraClient.ImportIssues(context.Background(), []*jira.Issue{
 		{
 			Type:        jira.IssueTypeStory,
 			Summary:     "Hello world",
@@ -244,7 +246,7 @@ func TestImportManyIssuesWAuthError(t *testing.T) {
 	descriptionBDoc.AddParagraph().
 		AddText("This is another paragraph", jira.ADFTextMode{})
 
-	resp, err := jiraClient.ImportIssues([]*jira.Issue{
+	resp, err := jiraClient.ImportIssues(context.Background(), []*jira.Issue{
 		{
 			Type:        jira.IssueTypeStory,
 			Summary:     "Hello world A",
@@ -280,7 +282,7 @@ func TestImportManyIssuesWError(t *testing.T) {
 	descriptionBDoc.AddParagraph().
 		AddText("This is another paragraph", jira.ADFTextMode{})
 
-	resp, err := jiraClient.ImportIssues([]*jira.Issue{
+	resp, err := jiraClient.ImportIssues(context.Background(), []*jira.Issue{
 		{
 			Type:        jira.IssueTypeStory,
 			Summary:     "Hello world A",
@@ -323,7 +325,7 @@ func TestImportManyIssuesWErrorAll(t *testing.T) {
 	descriptionBDoc.AddParagraph().
 		AddText("This is another paragraph", jira.ADFTextMode{})
 
-	resp, err := jiraClient.ImportIssues([]*jira.Issue{
+	resp, err := jiraClient.ImportIssues(context.Background(), []*jira.Issue{
 		{
 			Type:        jira.IssueTypeStory,
 			Summary:     "Hello world A",
@@ -347,3 +349,43 @@ func TestImportManyIssuesWErrorAll(t *testing.T) {
 	require.Error(t, resp[0].Err)
 	require.Error(t, resp[1].Err)
 }
+
+func TestSearchIssues(t *testing.T) {
+	tc := newTestConfig(t)
+	jiraClient := newJiraClient(tc)
+	gjc := newGoJiraClient(t, tc)
+
+	descriptionDoc := jira.NewADFDocument()
+	descriptionDoc.AddParagraph().
+		AddText("This is a paragraph", jira.ADFTextMode{})
+
+	resp, err := jiraClient.ImportIssues(context.Background(), []*jira.Issue{
+		{
+			Type:        jira.IssueTypeBug,
+			Summary:     "Hello world of searches",
+			Description: descriptionDoc,
+			EpicKey:     tc.jiraEpicKey,
+			ProjectKey:  tc.jiraProjectKey,
+			Labels:      []string{"label-1"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp, 1)
+	defer gjc.Issue.Delete(resp[0].NewIssueKey)
+
+	issues, err := jiraClient.SearchIssues(
+		context.Background(),
+		fmt.Sprintf("key = %s", resp[0].NewIssueKey),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(t, resp[0].NewIssueKey, issues[0].Key)
+	require.Equal(t, tc.jiraProjectKey, issues[0].ProjectKey)
+	require.Equal(t, jira.IssueTypeBug, issues[0].Type)
+	require.Equal(t, "Hello world of searches", issues[0].Summary)
+	require.Equal(t, tc.jiraEpicKey, issues[0].EpicKey)
+	require.Equal(t, []string{"label-1"}, issues[0].Labels)
+	require.Equal(
+		t, "This is a paragraph", issues[0].Description.PlainText(),
+	)
+}
@@ -1,6 +1,8 @@
 package integration_test
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -21,7 +23,7 @@ func TestTrackerCreateSingleIssue(t *testing.T) {
 			},
 		},
 	}
-	err := trackerService.ImportIssues(issues)
+	err := trackerService.ImportIssues(context.Background(), issues)
 	require.NoError(t, err)
 
 	issue, _, err := gjc.Issue.Get(issues[0].ID, nil)
@@ -55,7 +57,7 @@ func TestTrackerCreateManyIssues(t *testing.T) {
 			Epic: &domain.Epic{ID: tc.jiraEpicKey},
 		},
 	}
-	err := trackerService.ImportIssues(issues)
+	err := trackerService.ImportIssues(context.Background(), issues)
 	require.NoError(t, err)
 
 	issue, _, err := gjc.Issue.Get(issues[0].ID, nil)
@@ -112,7 +114,7 @@ func TestTrackerMapsDescriptionDocument(t *testing.T) {
 			},
 		},
 	}
-	err := trackerService.ImportIssues(issues)
+	err := trackerService.ImportIssues(context.Background(), issues)
 	require.NoError(t, err)
 
 	issue, _, err := gjc.Issue.Get(issues[0].ID, nil)
@@ -123,3 +125,34 @@ func TestTrackerMapsDescriptionDocument(t *testing.T) {
 Test paragraph. *Bold sentence.*`,
 		issue.Fields.Description)
 }
+
+func TestTrackerExportIssues(t *testing.T) {
+	tc := newTestConfig(t)
+	trackerService := newTrackerService(t, tc)
+	gjc := newGoJiraClient(t, tc)
+
+	description := &domain.Document{}
+	description.AddHeading(domain.HeadingLevel3, "Paragraph coming up")
+	description.AddParagraph().AddText("Test paragraph.", domain.TextMode{})
+	issues := []*domain.Issue{
+		{
+			Type:        domain.IssueTypeBug,
+			Title:       "Hello world 1",
+			Description: description,
+			Labels: []domain.Label{
+				{Label: "label-1"},
+			},
+			Epic: &domain.Epic{ID: tc.jiraEpicKey},
+		},
+	}
+	err := trackerService.ImportIssues(context.Background(), issues)
+	require.NoError(t, err)
+	defer gjc.Issue.Delete(issues[0].ID)
+
+	exportedIssues, err := trackerService.ExportIssues(
+		context.Background(),
+		fmt.Sprintf("key = %s", issues[0].ID),
+	)
+	require.NoError(t, err)
+	require.Equal(t, issues, exportedIssues)
+}
@@ -1,23 +1,57 @@
 package jira
 
+import (
+	"strconv"
+	"time"
+)
+
 /******************************************************************************
  * Interfaces
  *****************************************************************************/
 
 type ADFTextMode struct {
-	Strong bool
-	Em     bool
-	Code   bool
-	Strike bool
+	Strong    bool
+	Em        bool
+	Code      bool
+	Strike    bool
+	Underline bool
+	Sub       bool
+	Sup       bool
+
+	// Color is a lowercase hex RGB code, e.g. "#ff5630"
+	Color string
 }
 
 type ADFNodeText interface {
 	AddText(text string, mode ADFTextMode)
 	AddLink(text string, url string, mode ADFTextMode)
+	AddHardBreak()
+	AddMention(accountID string, text string)
+	AddEmoji(shortName string)
+	AddDate(date time.Time)
+	AddInlineCard(url string)
+}
+
+// ADFNodeBlocks is a node which contains blocks: the document, a list item, a
+// quote or a panel.
+type ADFNodeBlocks interface {
+	AddParagraph() ADFNodeText
+	AddBulletList() ADFNodeList
+	AddOrderedList() ADFNodeList
+	AddCodeBlock(language string, code string)
+	AddExternalImage(url string, altText string)
+	AddFileImage(mediaID string, altText string)
+}
+
+// ADFNodeListItem is an item of a list. Text is added to the paragraph the
+// item starts with and the blocks added to it follow that paragraph.
+type ADFNodeListItem interface {
+	ADFNodeText
+	ADFNodeBlocks
 }
 
 type ADFNodeList interface {
-	AddItem() ADFNodeText
+	AddItem() ADFNodeListItem
 }
 
 type ADFHeadingLevel int
@@ -31,12 +65,65 @@ const (
 	ADFHeadingLevel6
 )
 
-type ADFDocument interface {
-	AddParagraph() ADFNodeText
-	AddBulletList() ADFNodeList
-	AddOrderedList() ADFNodeList
-	AddCodeBlock(language string, code string)
+type ADFPanelType int
+
+const (
+	ADFPanelTypeInfo ADFPanelType = iota
+	ADFPanelTypeNote
+	ADFPanelTypeSuccess
+	ADFPanelTypeWarning
+	ADFPanelTypeError
+)
+
+// ADFNodePanel is a panel. Unlike quotes and list items, panels can contain
+// headings.
+type ADFNodePanel interface {
+	ADFNodeBlocks
+	AddHeading(level ADFHeadingLevel, text string)
+}
+
+type ADFAlignment int
+
+const (
+	ADFAlignmentStart ADFAlignment = iota
+	ADFAlignmentCenter
+	ADFAlignmentEnd
+)
+
+// ADFNodeTableRow is a row of a table. Each cell holds a paragraph with the
+// given alignment.
+type ADFNodeTableRow interface {
+	AddHeaderCell(alignment ADFAlignment) ADFNodeText
+	AddCell(alignment ADFAlignment) ADFNodeText
+}
+
+type ADFNodeTable interface {
+	AddRow() ADFNodeTableRow
+}
+
+// ADFNodeTaskList is a list of items which are either to do or done. Task
+// lists can only be nested in task lists.
+type ADFNodeTaskList interface {
+	AddItem(isDone bool) ADFNodeText
+	AddTaskList() ADFNodeTaskList
+}
+
+// ADFNodeExpand is a section which is collapsed under its title. Expands
+// can contain every block but other expands. Rules can only be added to
+// the document and to expands.
+type ADFNodeExpand interface {
+	ADFNodeBlocks
 	AddHeading(level ADFHeadingLevel, text string)
+	AddBlockquote() ADFNodeBlocks
+	AddPanel(panelType ADFPanelType) ADFNodePanel
+	AddTable() ADFNodeTable
+	AddTaskList() ADFNodeTaskList
+	AddRule()
+}
+
+type ADFDocument interface {
+	ADFNodeExpand
+	AddExpand(title string) ADFNodeExpand
 }
 
 func NewADFDocument() ADFDocument {
@@ -51,6 +138,9 @@ type adfDocument struct {
 	Version int        `json:"version"`
 	Type    string     `json:"type"`
 	Content []*adfNode `json:"content"`
+
+	// lastLocalID is the last ID given to a task list or a task
+	lastLocalID int
 }
 
 type adfNode struct {
@@ -73,7 +163,14 @@ type textNodeContainer struct {
 	*adfNode
 }
 
-func (c *textNodeContainer) AddText(text string, mode ADFTextMode) {
+// textMarks returns the marks of the text. Subscript wins when the text is
+// both subscript and superscript, as a text can only have one of them. Code
+// text has no other marks but links, which is all the schema allows it.
+func textMarks(mode ADFTextMode) []*adfNode {
+	if mode.Code {
+		return []*adfNode{{Type: "code"}}
+	}
+
 	marks := []*adfNode{}
 	if mode.Strong {
 		marks = append(marks, &adfNode{Type: "strong"})
@@ -81,17 +178,40 @@ func (c *textNodeContainer) AddText(text string, mode ADFTextMode) {
 	if mode.Em {
 		marks = append(marks, &adfNode{Type: "em"})
 	}
-	if mode.Code {
-		marks = append(marks, &adfNode{Type: "code"})
-	}
 	if mode.Strike {
 		marks = append(marks, &adfNode{Type: "strike"})
 	}
+	if mode.Underline {
+		marks = append(marks, &adfNode{Type: "underline"})
+	}
+	if mode.Sub || mode.Sup {
+		subsupType := "sup"
+		if mode.Sub {
+			subsupType = "sub"
+		}
+		marks = append(marks, &adfNode{
+			Type: "subsup",
+			Attrs: map[string]interface{}{
+				"type": subsupType,
+			},
+		})
+	}
+	if mode.Color != "" {
+		marks = append(marks, &adfNode{
+			Type: "textColor",
+			Attrs: map[string]interface{}{
+				"color": mode.Color,
+			},
+		})
+	}
+	return marks
+}
 
+func (c *textNodeContainer) AddText(text string, mode ADFTextMode) {
 	node := &adfNode{
 		Type:  "text",
 		Text:  text,
-		Marks: marks,
+		Marks: textMarks(mode),
 	}
 	c.adfNode.Content = append(c.adfNode.Content, node)
 }
@@ -99,19 +219,7 @@ func (c *textNodeContainer) AddText(text string, mode ADFTextMode) {
 func (c *textNodeContainer) AddLink(
 	text string, linkURL string, mode ADFTextMode,
 ) {
-	marks := []*adfNode{}
-	if mode.Strong {
-		marks = append(marks, &adfNode{Type: "strong"})
-	}
-	if mode.Em {
-		marks = append(marks, &adfNode{Type: "em"})
-	}
-	if mode.Code {
-		marks = append(marks, &adfNode{Type: "code"})
-	}
-	if mode.Strike {
-		marks = append(marks, &adfNode{Type: "strike"})
-	}
+	marks := textMarks(mode)
 	marks = append(marks, &adfNode{
 		Type: "link",
 		Attrs: map[string]interface{}{
@@ -127,16 +235,70 @@ func (c *textNodeContainer) AddLink(
 	c.adfNode.Content = append(c.adfNode.Content, node)
 }
 
-func (d *adfDocument) AddParagraph() ADFNodeText {
+func (c *textNodeContainer) AddHardBreak() {
+	c.adfNode.Content = append(c.adfNode.Content, &adfNode{Type: "hardBreak"})
+}
+
+// AddMention adds a mention of the user with the account ID. The text is
+// shown to users who cannot see the mentioned user, e.g. "@Alice".
+func (c *textNodeContainer) AddMention(accountID string, text string) {
+	c.adfNode.Content = append(c.adfNode.Content, &adfNode{
+		Type: "mention",
+		Attrs: map[string]interface{}{
+			"id":   accountID,
+			"text": text,
+		},
+	})
+}
+
+// AddEmoji adds the emoji with the short name, e.g. ":warning:".
+func (c *textNodeContainer) AddEmoji(shortName string) {
+	c.adfNode.Content = append(c.adfNode.Content, &adfNode{
+		Type: "emoji",
+		Attrs: map[string]interface{}{
+			"shortName": shortName,
+		},
+	})
+}
+
+// AddDate adds a date, kept as a UNIX timestamp in milliseconds.
+func (c *textNodeContainer) AddDate(date time.Time) {
+	timestamp := date.UnixNano() / int64(time.Millisecond)
+	c.adfNode.Content = append(c.adfNode.Content, &adfNode{
+		Type: "date",
+		Attrs: map[string]interface{}{
+			"timestamp": strconv.FormatInt(timestamp, 10),
+		},
+	})
+}
+
+// AddInlineCard adds a smart link to the URL, which Jira shows as a card
+// with the title of the page or issue it links to.
+func (c *textNodeContainer) AddInlineCard(url string) {
+	c.adfNode.Content = append(c.adfNode.Content, &adfNode{
+		Type: "inlineCard",
+		Attrs: map[string]interface{}{
+			"url": url,
+		},
+	})
+}
+
+func addParagraphNode(content *[]*adfNode) ADFNodeText {
 	p := &adfNode{
 		Type:    "paragraph",
 		Content: []*adfNode{},
 	}
-	d.Content = append(d.Content, p)
+	*content = append(*content, p)
 	return &textNodeContainer{p}
 }
 
-func (d *adfDocument) AddHeading(headingLevel ADFHeadingLevel, text string) {
+func (d *adfDocument) AddParagraph() ADFNodeText {
+	return addParagraphNode(&d.Content)
+}
+
+func addHeadingNode(
+	content *[]*adfNode, headingLevel ADFHeadingLevel, text string,
+) {
 	headingLevelInt := 6
 	switch headingLevel {
 	case ADFHeadingLevel1:
@@ -163,42 +325,88 @@ func (d *adfDocument) AddHeading(headingLevel ADFHeadingLevel, text string) {
 			},
 		},
 	}
-	d.Content = append(d.Content, h)
+	*content = append(*content, h)
+}
+
+func (d *adfDocument) AddHeading(headingLevel ADFHeadingLevel, text string) {
+	addHeadingNode(&d.Content, headingLevel, text)
+}
+
+// blockNodeContainer adds blocks to the content of a node.
+type blockNodeContainer struct {
+	*adfNode
+}
+
+func (c *blockNodeContainer) AddParagraph() ADFNodeText {
+	return addParagraphNode(&c.adfNode.Content)
+}
+
+func (c *blockNodeContainer) AddHeading(
+	headingLevel ADFHeadingLevel, text string,
+) {
+	addHeadingNode(&c.adfNode.Content, headingLevel, text)
+}
+
+func (c *blockNodeContainer) AddOrderedList() ADFNodeList {
+	return addListNode(&c.adfNode.Content, "orderedList")
+}
+
+func (c *blockNodeContainer) AddBulletList() ADFNodeList {
+	return addListNode(&c.adfNode.Content, "bulletList")
+}
+
+func (c *blockNodeContainer) AddCodeBlock(language string, code string) {
+	addCodeBlockNode(&c.adfNode.Content, language, code)
+}
+
+func (c *blockNodeContainer) AddExternalImage(url string, altText string) {
+	addExternalImageNode(&c.adfNode.Content, url, altText)
+}
+
+func (c *blockNodeContainer) AddFileImage(mediaID string, altText string) {
+	addFileImageNode(&c.adfNode.Content, mediaID, altText)
 }
 
 type listNodeContainer struct {
 	*adfNode
 }
 
-func (l *listNodeContainer) AddItem() ADFNodeText {
+type listItemNodeContainer struct {
+	textNodeContainer
+	blockNodeContainer
+}
+
+func (l *listNodeContainer) AddItem() ADFNodeListItem {
 	p := &adfNode{Type: "paragraph"}
 	li := &adfNode{
 		Type:    "listItem",
 		Content: []*adfNode{p},
 	}
 	l.adfNode.Content = append(l.adfNode.Content, li)
-	return &textNodeContainer{p}
+	return &listItemNodeContainer{
+		textNodeContainer:  textNodeContainer{p},
+		blockNodeContainer: blockNodeContainer{li},
+	}
 }
 
-func (d *adfDocument) AddOrderedList() ADFNodeList {
+func addListNode(content *[]*adfNode, listType string) ADFNodeList {
 	l := &adfNode{
-		Type:    "orderedList",
+		Type:    listType,
 		Content: []*adfNode{},
 	}
-	d.Content = append(d.Content, l)
+	*content = append(*content, l)
 	return &listNodeContainer{l}
 }
 
+func (d *adfDocument) AddOrderedList() ADFNodeList {
+	return addListNode(&d.Content, "orderedList")
+}
+
 func (d *adfDocument) AddBulletList() ADFNodeList {
-	l := &adfNode{
-		Type:    "bulletList",
-		Content: []*adfNode{},
-	}
-	d.Content = append(d.Content, l)
-	return &listNodeContainer{l}
+	return addListNode(&d.Content, "bulletList")
 }
 
-func (d *adfDocument) AddCodeBlock(language string, code string) {
+func addCodeBlockNode(content *[]*adfNode, language string, code string) {
 	cb := &adfNode{
 		Type: "codeBlock",
 		Attrs: map[string]interface{}{
@@ -211,5 +419,260 @@ func (d *adfDocument) AddCodeBlock(language string, code string) {
 			},
 		},
 	}
-	d.Content = append(d.Content, cb)
+	*content = append(*content, cb)
+}
+
+func (d *adfDocument) AddCodeBlock(language string, code string) {
+	addCodeBlockNode(&d.Content, language, code)
+}
+
+func addExternalImageNode(content *[]*adfNode, url string, altText string) {
+	addMediaSingleNode(content, &adfNode{
+		Type: "media",
+		Attrs: map[string]interface{}{
+			"type": "external",
+			"url":  url,
+		},
+	}, altText)
+}
+
+func (d *adfDocument) AddExternalImage(url string, altText string) {
+	addExternalImageNode(&d.Content, url, altText)
+}
+
+// addFileImageNode adds an image of the Jira media store, such as the file
+// of an attachment of the issue, by its media ID.
+func addFileImageNode(content *[]*adfNode, mediaID string, altText string) {
+	addMediaSingleNode(content, &adfNode{
+		Type: "media",
+		Attrs: map[string]interface{}{
+			"type":       "file",
+			"id":         mediaID,
+			"collection": "",
+		},
+	}, altText)
+}
+
+func (d *adfDocument) AddFileImage(mediaID string, altText string) {
+	addFileImageNode(&d.Content, mediaID, altText)
+}
+
+func addMediaSingleNode(content *[]*adfNode, media *adfNode, altText string) {
+	if altText != "" {
+		media.Attrs["alt"] = altText
+	}
+	ms := &adfNode{
+		Type: "mediaSingle",
+		Attrs: map[string]interface{}{
+			"layout": "center",
+		},
+		Content: []*adfNode{media},
+	}
+	*content = append(*content, ms)
+}
+
+func addBlockquoteNode(content *[]*adfNode) ADFNodeBlocks {
+	q := &adfNode{
+		Type:    "blockquote",
+		Content: []*adfNode{},
+	}
+	*content = append(*content, q)
+	return &blockNodeContainer{q}
+}
+
+func (d *adfDocument) AddBlockquote() ADFNodeBlocks {
+	return addBlockquoteNode(&d.Content)
+}
+
+func addPanelNode(content *[]*adfNode, panelType ADFPanelType) ADFNodePanel {
+	panelTypeName := "info"
+	switch panelType {
+	case ADFPanelTypeNote:
+		panelTypeName = "note"
+	case ADFPanelTypeSuccess:
+		panelTypeName = "success"
+	case ADFPanelTypeWarning:
+		panelTypeName = "warning"
+	case ADFPanelTypeError:
+		panelTypeName = "error"
+	}
+
+	p := &adfNode{
+		Type: "panel",
+		Attrs: map[string]interface{}{
+			"panelType": panelTypeName,
+		},
+		Content: []*adfNode{},
+	}
+	*content = append(*content, p)
+	return &blockNodeContainer{p}
+}
+
+func (d *adfDocument) AddPanel(panelType ADFPanelType) ADFNodePanel {
+	return addPanelNode(&d.Content, panelType)
+}
+
+type tableNodeContainer struct {
+	*adfNode
+}
+
+type tableRowNodeContainer struct {
+	*adfNode
+}
+
+func (t *tableNodeContainer) AddRow() ADFNodeTableRow {
+	r := &adfNode{
+		Type:    "tableRow",
+		Content: []*adfNode{},
+	}
+	t.adfNode.Content = append(t.adfNode.Content, r)
+	return &tableRowNodeContainer{r}
+}
+
+func (r *tableRowNodeContainer) addCell(
+	cellType string, alignment ADFAlignment,
+) ADFNodeText {
+	p := &adfNode{
+		Type:    "paragraph",
+		Content: []*adfNode{},
+	}
+	align := ""
+	switch alignment {
+	case ADFAlignmentCenter:
+		align = "center"
+	case ADFAlignmentEnd:
+		align = "end"
+	}
+	if align != "" {
+		p.Marks = []*adfNode{
+			{
+				Type: "alignment",
+				Attrs: map[string]interface{}{
+					"align": align,
+				},
+			},
+		}
+	}
+
+	c := &adfNode{
+		Type:    cellType,
+		Content: []*adfNode{p},
+	}
+	r.adfNode.Content = append(r.adfNode.Content, c)
+	return &textNodeContainer{p}
+}
+
+func (r *tableRowNodeContainer) AddHeaderCell(
+	alignment ADFAlignment,
+) ADFNodeText {
+	return r.addCell("tableHeader", alignment)
+}
+
+func (r *tableRowNodeContainer) AddCell(alignment ADFAlignment) ADFNodeText {
+	return r.addCell("tableCell", alignment)
+}
+
+func addTableNode(content *[]*adfNode) ADFNodeTable {
+	t := &adfNode{
+		Type: "table",
+		Attrs: map[string]interface{}{
+			"isNumberColumnEnabled": false,
+			"layout":                "default",
+		},
+		Content: []*adfNode{},
+	}
+	*content = append(*content, t)
+	return &tableNodeContainer{t}
+}
+
+func (d *adfDocument) AddTable() ADFNodeTable {
+	return addTableNode(&d.Content)
+}
+
+type taskListNodeContainer struct {
+	*adfNode
+	lastLocalID *int
+}
+
+func (l *taskListNodeContainer) AddItem(isDone bool) ADFNodeText {
+	*l.lastLocalID++
+	state := "TODO"
+	if isDone {
+		state = "DONE"
+	}
+	ti := &adfNode{
+		Type: "taskItem",
+		Attrs: map[string]interface{}{
+			"localId": strconv.Itoa(*l.lastLocalID),
+			"state":   state,
+		},
+	}
+	l.adfNode.Content = append(l.adfNode.Content, ti)
+	return &textNodeContainer{ti}
+}
+
+func addTaskListNode(
+	content *[]*adfNode, lastLocalID *int,
+) ADFNodeTaskList {
+	*lastLocalID++
+	tl := &adfNode{
+		Type: "taskList",
+		Attrs: map[string]interface{}{
+			"localId": strconv.Itoa(*lastLocalID),
+		},
+		Content: []*adfNode{},
+	}
+	*content = append(*content, tl)
+	return &taskListNodeContainer{tl, lastLocalID}
+}
+
+func (l *taskListNodeContainer) AddTaskList() ADFNodeTaskList {
+	return addTaskListNode(&l.adfNode.Content, l.lastLocalID)
+}
+
+func (d *adfDocument) AddTaskList() ADFNodeTaskList {
+	return addTaskListNode(&d.Content, &d.lastLocalID)
+}
+
+// expandNodeContainer adds blocks to an expand. Task lists in the expand
+// share the local IDs of the document.
+type expandNodeContainer struct {
+	blockNodeContainer
+	lastLocalID *int
+}
+
+func (c *expandNodeContainer) AddBlockquote() ADFNodeBlocks {
+	return addBlockquoteNode(&c.adfNode.Content)
+}
+
+func (c *expandNodeContainer) AddPanel(panelType ADFPanelType) ADFNodePanel {
+	return addPanelNode(&c.adfNode.Content, panelType)
+}
+
+func (c *expandNodeContainer) AddTable() ADFNodeTable {
+	return addTableNode(&c.adfNode.Content)
+}
+
+func (c *expandNodeContainer) AddTaskList() ADFNodeTaskList {
+	return addTaskListNode(&c.adfNode.Content, c.lastLocalID)
+}
+
+func (c *expandNodeContainer) AddRule() {
+	c.adfNode.Content = append(c.adfNode.Content, &adfNode{Type: "rule"})
+}
+
+func (d *adfDocument) AddRule() {
+	d.Content = append(d.Content, &adfNode{Type: "rule"})
+}
+
+func (d *adfDocument) AddExpand(title string) ADFNodeExpand {
+	e := &adfNode{
+		Type: "expand",
+		Attrs: map[string]interface{}{
+			"title": title,
+		},
+		Content: []*adfNode{},
+	}
+	d.Content = append(d.Content, e)
+	return &expandNodeContainer{blockNodeContainer{e}, &d.lastLocalID}
 }
@@ -0,0 +1,188 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+/******************************************************************************
+ * Parsed Documents
+ *****************************************************************************/
+
+// ADFNode is a node of a parsed Atlassian Document Format document. Nodes of
+// any type are parsed in the same structure, so node types this package does
+// not know about are kept as they are.
+type ADFNode struct {
+	Type    string
+	Attrs   map[string]interface{}
+	Content []*ADFNode
+	Text    string
+	Marks   []*ADFMark
+
+	// Extra keeps the node fields which are not listed above (e.g. the
+	// version of the document node) so that encoding the node again does
+	// not lose them.
+	Extra map[string]json.RawMessage
+}
+
+// ADFMark is a mark applied to a parsed text node.
+type ADFMark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// ParseADF parses an Atlassian Document Format document. The root of the
+// returned tree is the "doc" node.
+func ParseADF(data []byte) (*ADFNode, error) {
+	doc := &ADFNode{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("Failed to parse document: %s", err)
+	}
+	if doc.Type != "doc" {
+		return nil, fmt.Errorf(
+			"Expected document node, found '%s' node", doc.Type,
+		)
+	}
+	return doc, nil
+}
+
+var adfNodeFields = map[string]bool{
+	"type":    true,
+	"attrs":   true,
+	"content": true,
+	"text":    true,
+	"marks":   true,
+}
+
+type adfNodeJSON struct {
+	Type    string                 `json:"type"`
+	Attrs   map[string]interface{} `json:"attrs"`
+	Content []*ADFNode             `json:"content"`
+	Text    string                 `json:"text"`
+	Marks   []*ADFMark             `json:"marks"`
+}
+
+func (n *ADFNode) UnmarshalJSON(data []byte) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	nodeJSON := adfNodeJSON{}
+	if err := json.Unmarshal(data, &nodeJSON); err != nil {
+		return err
+	}
+	if nodeJSON.Type == "" {
+		return errors.New("Node type is missing")
+	}
+
+	*n = ADFNode{
+		Type:    nodeJSON.Type,
+		Attrs:   nodeJSON.Attrs,
+		Content: nodeJSON.Content,
+		Text:    nodeJSON.Text,
+		Marks:   nodeJSON.Marks,
+	}
+	for name, value := range fields {
+		if adfNodeFields[name] {
+			continue
+		}
+		if n.Extra == nil {
+			n.Extra = map[string]json.RawMessage{}
+		}
+		n.Extra[name] = value
+	}
+	return nil
+}
+
+func (n *ADFNode) MarshalJSON() ([]byte, error) {
+	fields := map[string]interface{}{}
+	for name, value := range n.Extra {
+		fields[name] = value
+	}
+	fields["type"] = n.Type
+	if n.Attrs != nil {
+		fields["attrs"] = n.Attrs
+	}
+	if n.Content != nil {
+		fields["content"] = n.Content
+	}
+	if n.Type == "text" || n.Text != "" {
+		fields["text"] = n.Text
+	}
+	if n.Marks != nil {
+		fields["marks"] = n.Marks
+	}
+	return json.Marshal(fields)
+}
+
+/******************************************************************************
+ * Navigation
+ *****************************************************************************/
+
+// AttrString returns the string attribute with the given name, or "" if the
+// node does not have such an attribute.
+func (n *ADFNode) AttrString(name string) string {
+	value, _ := n.Attrs[name].(string)
+	return value
+}
+
+// AttrInt returns the numeric attribute with the given name.
+func (n *ADFNode) AttrInt(name string) (int, bool) {
+	value, ok := n.Attrs[name].(float64)
+	return int(value), ok
+}
+
+// Mark returns the mark of the given type applied to the node, or nil.
+func (n *ADFNode) Mark(markType string) *ADFMark {
+	for _, mark := range n.Marks {
+		if mark.Type == markType {
+			return mark
+		}
+	}
+	return nil
+}
+
+// AttrString returns the string attribute with the given name, or "" if the
+// mark does not have such an attribute.
+func (m *ADFMark) AttrString(name string) string {
+	value, _ := m.Attrs[name].(string)
+	return value
+}
+
+// Walk visits the node and its descendants depth-first. The children of a
+// node are skipped when fn returns false for it.
+func (n *ADFNode) Walk(fn func(node *ADFNode) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, child := range n.Content {
+		child.Walk(fn)
+	}
+}
+
+// PlainText returns the text of the node and its descendants without
+// formatting. Inline nodes which are not text (mentions, emojis, etc.) are
+// replaced by the text they are displayed as.
+func (n *ADFNode) PlainText() string {
+	switch n.Type {
+	case "text":
+		return n.Text
+	case "hardBreak":
+		return "\n"
+	case "mention", "emoji", "status":
+		if text := n.AttrString("text"); text != "" {
+			return text
+		}
+		return n.AttrString("shortName")
+	case "inlineCard":
+		return n.AttrString("url")
+	}
+
+	text := ""
+	for _, child := range n.Content {
+		text += child.PlainText()
+	}
+	return text
+}
@@ -0,0 +1,183 @@
+package jira_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/glestaris/issuez/jira"
+	"github.com/stretchr/testify/require"
+)
+
+func readAsset(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile("../assets/" + name)
+	require.NoError(t, err)
+	return data
+}
+
+// schemaTypes returns the node and mark types defined in the ADF JSON schema.
+func schemaTypes(t *testing.T) (nodeTypes []string, markTypes []string) {
+	schema := struct {
+		Definitions map[string]struct {
+			Properties struct {
+				Type struct {
+					Enum []string `json:"enum"`
+				} `json:"type"`
+			} `json:"properties"`
+		} `json:"definitions"`
+	}{}
+	require.NoError(t, json.Unmarshal(readAsset(t, "full.json"), &schema))
+
+	for name, definition := range schema.Definitions {
+		enum := definition.Properties.Type.Enum
+		if len(enum) == 0 {
+			continue
+		}
+		if strings.HasSuffix(name, "_node") {
+			nodeTypes = append(nodeTypes, enum...)
+		} else if strings.HasSuffix(name, "_mark") {
+			markTypes = append(markTypes, enum...)
+		}
+	}
+	sort.Strings(nodeTypes)
+	sort.Strings(markTypes)
+	return
+}
+
+func TestParseADFRoundTrip(t *testing.T) {
+	for _, asset := range []string{"adf.json", "adf_all_nodes.json"} {
+		t.Run(asset, func(t *testing.T) {
+			data := readAsset(t, asset)
+			doc, err := jira.ParseADF(data)
+			require.NoError(t, err)
+
+			docJSON, err := json.Marshal(doc)
+			require.NoError(t, err)
+			require.JSONEq(t, string(data), string(docJSON))
+		})
+	}
+}
+
+func TestParseADFCoversSchema(t *testing.T) {
+	nodeTypes, markTypes := schemaTypes(t)
+	require.NotEmpty(t, nodeTypes)
+	require.NotEmpty(t, markTypes)
+
+	doc, err := jira.ParseADF(readAsset(t, "adf_all_nodes.json"))
+	require.NoError(t, err)
+
+	foundNodeTypes := map[string]bool{}
+	foundMarkTypes := map[string]bool{}
+	doc.Walk(func(node *jira.ADFNode) bool {
+		foundNodeTypes[node.Type] = true
+		for _, mark := range node.Marks {
+			foundMarkTypes[mark.Type] = true
+		}
+		return true
+	})
+	for _, nodeType := range nodeTypes {
+		require.True(t, foundNodeTypes[nodeType], "node type %s", nodeType)
+	}
+	for _, markType := range markTypes {
+		require.True(t, foundMarkTypes[markType], "mark type %s", markType)
+	}
+}
+
+func TestParseADFBuiltDocument(t *testing.T) {
+	builtDoc := jira.NewADFDocument()
+	builtDoc.AddHeading(jira.ADFHeadingLevel2, "Title")
+	p := builtDoc.AddParagraph()
+	p.AddText("Hello ", jira.ADFTextMode{})
+	p.AddLink("world", "https://google.com", jira.ADFTextMode{Strong: true})
+	builtDoc.AddBulletList().AddItem().AddText("Item", jira.ADFTextMode{})
+	builtDoc.AddCodeBlock("python", "x = 12\n")
+	builtDocJSON, err := json.Marshal(builtDoc)
+	require.NoError(t, err)
+
+	doc, err := jira.ParseADF(builtDocJSON)
+	require.NoError(t, err)
+	docJSON, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, string(builtDocJSON), string(docJSON))
+
+	require.Len(t, doc.Content, 4)
+	heading := doc.Content[0]
+	require.Equal(t, "heading", heading.Type)
+	level, ok := heading.AttrInt("level")
+	require.True(t, ok)
+	require.Equal(t, 2, level)
+	require.Equal(t, "Title", heading.PlainText())
+
+	link := doc.Content[1].Content[1]
+	require.Equal(t, "world", link.Text)
+	require.NotNil(t, link.Mark("strong"))
+	require.Nil(t, link.Mark("em"))
+	require.Equal(t, "https://google.com", link.Mark("link").AttrString("href"))
+
+	require.Equal(t, "python", doc.Content[3].AttrString("language"))
+	require.Equal(t, "", doc.Content[3].AttrString("missing"))
+}
+
+func TestParseADFUnknownNodes(t *testing.T) {
+	data := `{
+  "version": 1,
+  "type": "doc",
+  "content": [
+    {
+      "type": "futureNode",
+      "attrs": { "answer": 42 },
+      "futureField": { "a": [1, 2] },
+      "content": [{ "type": "text", "text": "Still here" }]
+    }
+  ]
+}`
+	doc, err := jira.ParseADF([]byte(data))
+	require.NoError(t, err)
+	require.Equal(t, "futureNode", doc.Content[0].Type)
+	require.Equal(t, "Still here", doc.PlainText())
+
+	docJSON, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, data, string(docJSON))
+}
+
+func TestParseADFPlainText(t *testing.T) {
+	doc, err := jira.ParseADF(readAsset(t, "adf_all_nodes.json"))
+	require.NoError(t, err)
+
+	paragraph := doc.Content[1]
+	require.Equal(
+		t,
+		"StrongEmStrikeCodeUnderlineLinkSubRedAnnotated\n@Alice😀"+
+			"In progresshttps://example.com/issue",
+		paragraph.PlainText(),
+	)
+}
+
+func TestParseADFWalkSkipsChildren(t *testing.T) {
+	doc, err := jira.ParseADF(readAsset(t, "adf.json"))
+	require.NoError(t, err)
+
+	visited := []string{}
+	doc.Walk(func(node *jira.ADFNode) bool {
+		visited = append(visited, node.Type)
+		return node.Type == "doc"
+	})
+	require.Equal(t, "doc", visited[0])
+	for _, nodeType := range visited[1:] {
+		require.NotEqual(t, "text", nodeType)
+	}
+}
+
+func TestParseADFErrors(t *testing.T) {
+	_, err := jira.ParseADF([]byte("not json"))
+	require.Error(t, err)
+
+	_, err = jira.ParseADF([]byte(`{"type": "paragraph"}`))
+	require.EqualError(t, err, "Expected document node, found 'paragraph' node")
+
+	_, err = jira.ParseADF([]byte(`{"type": "doc", "content": [{}]}`))
+	require.Error(t, err)
+}
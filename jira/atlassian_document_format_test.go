@@ -3,6 +3,7 @@ package jira_test
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/glestaris/issuez/jira"
@@ -129,6 +130,186 @@ func TestADFDocumentParagraph(t *testing.T) {
     `, string(docJSON))
 }
 
+func TestADFDocumentParagraphMarks(t *testing.T) {
+	doc := jira.NewADFDocument()
+	require.NotNil(t, doc)
+
+	p := doc.AddParagraph()
+	p.AddText("Underline", jira.ADFTextMode{Underline: true})
+	p.AddText("Sub", jira.ADFTextMode{Sub: true})
+	p.AddHardBreak()
+	p.AddLink("Sup", "https://google.com", jira.ADFTextMode{Sup: true})
+	p.AddText("Red", jira.ADFTextMode{Strong: true, Color: "#ff0000"})
+
+	docJSON, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "version": 1,
+  "type": "doc",
+  "content": [
+    {
+      "type": "paragraph",
+      "content": [
+        {
+          "type": "text",
+          "text": "Underline",
+          "marks": [{ "type": "underline" }]
+        },
+        {
+          "type": "text",
+          "text": "Sub",
+          "marks": [{ "type": "subsup", "attrs": { "type": "sub" } }]
+        },
+        { "type": "hardBreak" },
+        {
+          "type": "text",
+          "text": "Sup",
+          "marks": [
+            { "type": "subsup", "attrs": { "type": "sup" } },
+            { "type": "link", "attrs": { "href": "https://google.com" } }
+          ]
+        },
+        {
+          "type": "text",
+          "text": "Red",
+          "marks": [
+            { "type": "strong" },
+            { "type": "textColor", "attrs": { "color": "#ff0000" } }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`, string(docJSON))
+}
+
+func TestADFDocumentParagraphCodeMarks(t *testing.T) {
+	doc := jira.NewADFDocument()
+	require.NotNil(t, doc)
+
+	p := doc.AddParagraph()
+	p.AddText("x", jira.ADFTextMode{Code: true, Underline: true})
+	p.AddLink("y", "https://google.com", jira.ADFTextMode{
+		Code: true, Strong: true, Sup: true, Color: "#ff0000",
+	})
+
+	docJSON, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "version": 1,
+  "type": "doc",
+  "content": [
+    {
+      "type": "paragraph",
+      "content": [
+        { "type": "text", "text": "x", "marks": [{ "type": "code" }] },
+        {
+          "type": "text",
+          "text": "y",
+          "marks": [
+            { "type": "code" },
+            { "type": "link", "attrs": { "href": "https://google.com" } }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`, string(docJSON))
+}
+
+func TestADFDocumentParagraphInlineNodes(t *testing.T) {
+	doc := jira.NewADFDocument()
+	require.NotNil(t, doc)
+
+	p := doc.AddParagraph()
+	p.AddText("Ask ", jira.ADFTextMode{})
+	p.AddMention("5b10ac8d82e05b22cc7d4ef5", "@Alice Smith")
+	p.AddEmoji(":warning:")
+	p.AddDate(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC))
+	p.AddInlineCard("https://example.com/issue")
+
+	docJSON, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "version": 1,
+  "type": "doc",
+  "content": [
+    {
+      "type": "paragraph",
+      "content": [
+        { "type": "text", "text": "Ask " },
+        {
+          "type": "mention",
+          "attrs": {
+            "id": "5b10ac8d82e05b22cc7d4ef5",
+            "text": "@Alice Smith"
+          }
+        },
+        { "type": "emoji", "attrs": { "shortName": ":warning:" } },
+        { "type": "date", "attrs": { "timestamp": "1793491200000" } },
+        {
+          "type": "inlineCard",
+          "attrs": { "url": "https://example.com/issue" }
+        }
+      ]
+    }
+  ]
+}
+`, string(docJSON))
+}
+
+// TestADFDocumentParagraphMarksSchema checks the attributes of the marks
+// against their definitions in the ADF JSON schema.
+func TestADFDocumentParagraphMarksSchema(t *testing.T) {
+	schema := struct {
+		Definitions map[string]struct {
+			Properties struct {
+				Attrs struct {
+					Properties map[string]struct {
+						Enum    []string `json:"enum"`
+						Pattern string   `json:"pattern"`
+					} `json:"properties"`
+				} `json:"attrs"`
+			} `json:"properties"`
+		} `json:"definitions"`
+	}{}
+	require.NoError(t, json.Unmarshal(readAsset(t, "full.json"), &schema))
+
+	doc := jira.NewADFDocument()
+	p := doc.AddParagraph()
+	p.AddText("Underline", jira.ADFTextMode{Underline: true})
+	p.AddText("Sub", jira.ADFTextMode{Sub: true})
+	p.AddText("Sup", jira.ADFTextMode{Sup: true})
+	p.AddText("Red", jira.ADFTextMode{Color: "#ff5630"})
+	docJSON, err := json.Marshal(doc)
+	require.NoError(t, err)
+	parsedDoc, err := jira.ParseADF(docJSON)
+	require.NoError(t, err)
+
+	for _, text := range parsedDoc.Content[0].Content {
+		require.Len(t, text.Marks, 1)
+		mark := text.Marks[0]
+		definition, ok := schema.Definitions[mark.Type+"_mark"]
+		require.True(t, ok, "mark type %s", mark.Type)
+		attrs := definition.Properties.Attrs.Properties
+		require.Len(t, mark.Attrs, len(attrs), "mark type %s", mark.Type)
+		for name, attr := range attrs {
+			value := mark.AttrString(name)
+			if len(attr.Enum) != 0 {
+				require.Contains(t, attr.Enum, value)
+			}
+			if attr.Pattern != "" {
+				require.Regexp(t, attr.Pattern, value)
+			}
+		}
+	}
+}
+
 func TestADFDocumentOrderedList(t *testing.T) {
 	doc := jira.NewADFDocument()
 	require.NotNil(t, doc)
@@ -194,6 +375,321 @@ func TestADFDocumentOrderedList(t *testing.T) {
     `, string(docJSON))
 }
 
+func TestADFDocumentNestedList(t *testing.T) {
+	doc := jira.NewADFDocument()
+	require.NotNil(t, doc)
+
+	item := doc.AddBulletList().AddItem()
+	item.AddText("Parent", jira.ADFTextMode{})
+	item.AddOrderedList().AddItem().AddText("Child", jira.ADFTextMode{})
+	item.AddCodeBlock("python", "x = 12\n")
+
+	docJSON, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "version": 1,
+  "type": "doc",
+  "content": [
+    {
+      "type": "bulletList",
+      "content": [
+        {
+          "type": "listItem",
+          "content": [
+            {
+              "type": "paragraph",
+              "content": [
+                {
+                  "type": "text",
+                  "text": "Parent"
+                }
+              ]
+            },
+            {
+              "type": "orderedList",
+              "content": [
+                {
+                  "type": "listItem",
+                  "content": [
+                    {
+                      "type": "paragraph",
+                      "content": [
+                        {
+                          "type": "text",
+                          "text": "Child"
+                        }
+                      ]
+                    }
+                  ]
+                }
+              ]
+            },
+            {
+              "type": "codeBlock",
+              "attrs": {
+                "language": "python"
+              },
+              "content": [
+                {
+                  "type": "text",
+                  "text": "x = 12\n"
+                }
+              ]
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+    `, string(docJSON))
+}
+
+func TestADFDocumentQuotes(t *testing.T) {
+	doc := jira.NewADFDocument()
+	require.NotNil(t, doc)
+
+	doc.AddBlockquote().AddParagraph().AddText("Quoted", jira.ADFTextMode{})
+	panel := doc.AddPanel(jira.ADFPanelTypeWarning)
+	panel.AddHeading(jira.ADFHeadingLevel3, "Careful")
+
+	docJSON, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "version": 1,
+  "type": "doc",
+  "content": [
+    {
+      "type": "blockquote",
+      "content": [
+        {
+          "type": "paragraph",
+          "content": [
+            {
+              "type": "text",
+              "text": "Quoted"
+            }
+          ]
+        }
+      ]
+    },
+    {
+      "type": "panel",
+      "attrs": {
+        "panelType": "warning"
+      },
+      "content": [
+        {
+          "type": "heading",
+          "attrs": {
+            "level": 3
+          },
+          "content": [
+            {
+              "type": "text",
+              "text": "Careful"
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+	`, string(docJSON))
+}
+
+func TestADFDocumentTaskList(t *testing.T) {
+	doc := jira.NewADFDocument()
+	require.NotNil(t, doc)
+
+	tasks := doc.AddTaskList()
+	tasks.AddItem(false).AddText("To do", jira.ADFTextMode{})
+	tasks.AddTaskList().AddItem(true).AddText(
+		"Done", jira.ADFTextMode{Strong: true},
+	)
+
+	docJSON, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "version": 1,
+  "type": "doc",
+  "content": [
+    {
+      "type": "taskList",
+      "attrs": { "localId": "1" },
+      "content": [
+        {
+          "type": "taskItem",
+          "attrs": { "localId": "2", "state": "TODO" },
+          "content": [{ "type": "text", "text": "To do" }]
+        },
+        {
+          "type": "taskList",
+          "attrs": { "localId": "3" },
+          "content": [
+            {
+              "type": "taskItem",
+              "attrs": { "localId": "4", "state": "DONE" },
+              "content": [
+                {
+                  "type": "text",
+                  "text": "Done",
+                  "marks": [{ "type": "strong" }]
+                }
+              ]
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`, string(docJSON))
+}
+
+func TestADFDocumentExpand(t *testing.T) {
+	doc := jira.NewADFDocument()
+	require.NotNil(t, doc)
+
+	doc.AddTaskList().AddItem(false).AddText("Reproduce", jira.ADFTextMode{})
+	expand := doc.AddExpand("Stack trace")
+	expand.AddCodeBlock("", "panic: oops")
+	expand.AddRule()
+	expand.AddTaskList().AddItem(true).AddText("Fix", jira.ADFTextMode{})
+
+	docJSON, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "version": 1,
+  "type": "doc",
+  "content": [
+    {
+      "type": "taskList",
+      "attrs": { "localId": "1" },
+      "content": [
+        {
+          "type": "taskItem",
+          "attrs": { "localId": "2", "state": "TODO" },
+          "content": [{ "type": "text", "text": "Reproduce" }]
+        }
+      ]
+    },
+    {
+      "type": "expand",
+      "attrs": { "title": "Stack trace" },
+      "content": [
+        {
+          "type": "codeBlock",
+          "attrs": { "language": "" },
+          "content": [{ "type": "text", "text": "panic: oops" }]
+        },
+        { "type": "rule" },
+        {
+          "type": "taskList",
+          "attrs": { "localId": "3" },
+          "content": [
+            {
+              "type": "taskItem",
+              "attrs": { "localId": "4", "state": "DONE" },
+              "content": [{ "type": "text", "text": "Fix" }]
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`, string(docJSON))
+}
+
+func TestADFDocumentTable(t *testing.T) {
+	doc := jira.NewADFDocument()
+	require.NotNil(t, doc)
+
+	table := doc.AddTable()
+	table.AddRow().AddHeaderCell(jira.ADFAlignmentCenter).AddText(
+		"Header", jira.ADFTextMode{},
+	)
+	table.AddRow().AddCell(jira.ADFAlignmentStart).AddText(
+		"Cell", jira.ADFTextMode{Strong: true},
+	)
+
+	docJSON, err := json.Marshal(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+{
+  "version": 1,
+  "type": "doc",
+  "content": [
+    {
+      "type": "table",
+      "attrs": {
+        "isNumberColumnEnabled": false,
+        "layout": "default"
+      },
+      "content": [
+        {
+          "type": "tableRow",
+          "content": [
+            {
+              "type": "tableHeader",
+              "content": [
+                {
+                  "type": "paragraph",
+                  "content": [
+                    {
+                      "type": "text",
+                      "text": "Header"
+                    }
+                  ],
+                  "marks": [
+                    {
+                      "type": "alignment",
+                      "attrs": {
+                        "align": "center"
+                      }
+                    }
+                  ]
+                }
+              ]
+            }
+          ]
+        },
+        {
+          "type": "tableRow",
+          "content": [
+            {
+              "type": "tableCell",
+              "content": [
+                {
+                  "type": "paragraph",
+                  "content": [
+                    {
+                      "type": "text",
+                      "text": "Cell",
+                      "marks": [
+                        {
+                          "type": "strong"
+                        }
+                      ]
+                    }
+                  ]
+                }
+              ]
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+	`, string(docJSON))
+}
+
 func TestADFDocumentMany(t *testing.T) {
 	doc := jira.NewADFDocument()
 	require.NotNil(t, doc)
@@ -2,12 +2,18 @@ package jira
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
+	"mime/multipart"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -54,6 +60,25 @@ type Issue struct {
 	Description ADFDocument
 	EpicKey     string
 	Labels      []string
+
+	// TypeName is the name of the issue type, when it is not one of the
+	// types of Type (e.g. "Release")
+	TypeName string
+
+	// FixVersions are the names of the versions of the project the issue is
+	// fixed in
+	FixVersions []string
+}
+
+func issueTypeFromName(name string) IssueType {
+	switch name {
+	case "Bug":
+		return IssueTypeBug
+	case "Task", "Sub-task", "Subtask":
+		return IssueTypeTask
+	default:
+		return IssueTypeStory
+	}
 }
 
 func (i Issue) String() string {
@@ -90,10 +115,15 @@ type issImpReqIssue struct {
 		Parent      struct {
 			Key string `json:"key,omitempty"`
 		} `json:"parent,omitempty"`
-		Labels []string `json:"labels"`
+		Labels      []string              `json:"labels"`
+		FixVersions []issImpReqFixVersion `json:"fixVersions,omitempty"`
 	} `json:"fields"`
 }
 
+type issImpReqFixVersion struct {
+	Name string `json:"name"`
+}
+
 type issImpReq struct {
 	Issues []issImpReqIssue `json:"issueUpdates"`
 }
@@ -116,13 +146,40 @@ type issImpResp struct {
 	Errors []issImpRespError `json:"errors"`
 }
 
+// maxBulkIssues is the maximum number of issues the JIRA bulk create API
+// accepts in a single request.
+const maxBulkIssues = 50
+
+// ImportIssues creates the issues in chunks of the size the JIRA bulk create
+// API accepts. When a chunk fails, e.g. because the context is cancelled,
+// the response of the chunks created before it is returned with the error.
 func (c *Client) ImportIssues(
-	issues []*Issue,
+	ctx context.Context, issues []*Issue,
 ) (ImportIssuesResponse, error) {
 	if len(issues) == 0 {
 		return ImportIssuesResponse{}, nil
 	}
 
+	retVal := make(ImportIssuesResponse, 0, len(issues))
+	for start := 0; start < len(issues); start += maxBulkIssues {
+		end := start + maxBulkIssues
+		if end > len(issues) {
+			end = len(issues)
+		}
+
+		chunkResp, err := c.importIssuesChunk(ctx, issues[start:end])
+		if err != nil {
+			return retVal, err
+		}
+		retVal = append(retVal, chunkResp...)
+	}
+
+	return retVal, nil
+}
+
+func (c *Client) importIssuesChunk(
+	ctx context.Context, issues []*Issue,
+) (ImportIssuesResponse, error) {
 	reqBody := issImpReq{
 		Issues: make([]issImpReqIssue, len(issues)),
 	}
@@ -130,12 +187,21 @@ func (c *Client) ImportIssues(
 		reqBodyIssue := issImpReqIssue{}
 		reqBodyIssue.Fields.Project.Key = issue.ProjectKey
 		reqBodyIssue.Fields.IssueType.Name = issue.String()
+		if issue.TypeName != "" {
+			reqBodyIssue.Fields.IssueType.Name = issue.TypeName
+		}
 		reqBodyIssue.Fields.Summary = issue.Summary
 		reqBodyIssue.Fields.Description = issue.Description
 		if issue.EpicKey != "" {
 			reqBodyIssue.Fields.Parent.Key = issue.EpicKey
 		}
 		reqBodyIssue.Fields.Labels = issue.Labels
+		for _, fixVersion := range issue.FixVersions {
+			reqBodyIssue.Fields.FixVersions = append(
+				reqBodyIssue.Fields.FixVersions,
+				issImpReqFixVersion{Name: fixVersion},
+			)
+		}
 
 		reqBody.Issues[i] = reqBodyIssue
 	}
@@ -145,11 +211,12 @@ func (c *Client) ImportIssues(
 	}
 
 	req, resp, err := c.performRequest(
-		"POST", "/rest/api/3/issue/bulk", reqBodyBytes,
+		ctx, "POST", "/rest/api/3/issue/bulk", reqBodyBytes,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to perform request: %s", err)
+		return nil, err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != 201 && resp.StatusCode != 400 {
 		c.logFailedRequest(req, resp)
 		return nil, fmt.Errorf(
@@ -175,9 +242,12 @@ func (c *Client) ImportIssues(
 	}
 	i := 0
 	for _, respIssue := range respBody.Issues {
-		for retVal[i].Err != nil {
+		for i < len(retVal) && retVal[i].Err != nil {
 			i++
 		}
+		if i >= len(retVal) {
+			break
+		}
 		retVal[i].NewIssueKey = respIssue.Key
 		i++
 	}
@@ -185,15 +255,396 @@ func (c *Client) ImportIssues(
 	return retVal, nil
 }
 
+/******************************************************************************
+ * Update JIRA Issues
+ *****************************************************************************/
+
+type issUpdateReq struct {
+	Fields struct {
+		Description ADFDocument `json:"description"`
+	} `json:"fields"`
+}
+
+func (c *Client) UpdateIssueDescription(
+	ctx context.Context, issueKey string, description ADFDocument,
+) error {
+	reqBody := issUpdateReq{}
+	reqBody.Fields.Description = description
+	reqBodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize request body: %s", err)
+	}
+
+	req, resp, err := c.performRequest(
+		ctx, "PUT", "/rest/api/3/issue/"+url.PathEscape(issueKey),
+		reqBodyBytes,
+	)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 204 {
+		c.logFailedRequest(req, resp)
+		return fmt.Errorf(
+			"Failed to update issue %s: %s", issueKey, resp.Status,
+		)
+	}
+
+	return nil
+}
+
+/******************************************************************************
+ * JIRA Issue Attachments
+ *****************************************************************************/
+
+// Attachment is a file attached to an issue. ContentURL is the URL the
+// content of the file can be downloaded from.
+type Attachment struct {
+	ID         string `json:"id"`
+	Filename   string `json:"filename"`
+	MimeType   string `json:"mimeType"`
+	Size       int64  `json:"size"`
+	ContentURL string `json:"content"`
+}
+
+// AddAttachment uploads the content as a file attached to the issue.
+func (c *Client) AddAttachment(
+	ctx context.Context, issueKey string, filename string, content io.Reader,
+) (*Attachment, error) {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create request body: %s", err)
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return nil, fmt.Errorf("Failed to read '%s': %s", filename, err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, fmt.Errorf("Failed to create request body: %s", err)
+	}
+
+	req, resp, err := c.performRequestWithBody(
+		ctx,
+		"POST",
+		"/rest/api/3/issue/"+url.PathEscape(issueKey)+"/attachments",
+		form.FormDataContentType(),
+		body,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		c.logFailedRequest(req, resp)
+		return nil, fmt.Errorf(
+			"Failed to attach '%s' to issue %s: %s",
+			filename, issueKey, resp.Status,
+		)
+	}
+
+	// the API responds with the list of the attachments it created
+	attachments := []*Attachment{}
+	err = json.NewDecoder(resp.Body).Decode(&attachments)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse API response: %s", err)
+	}
+	if len(attachments) != 1 {
+		return nil, fmt.Errorf(
+			"Expected 1 attachment in API response, got %d",
+			len(attachments),
+		)
+	}
+
+	return attachments[0], nil
+}
+
+// mediaFileRe matches the ID of the file in the URL of the Jira media store
+// the content of an attachment redirects to.
+var mediaFileRe = regexp.MustCompile(`/file/([0-9a-fA-F-]+)/`)
+
+// AttachmentMediaID returns the ID of the file of the attachment in the Jira
+// media store, which ADF media nodes embed the attachment by. The API only
+// tells it in the URL the content of the attachment redirects to.
+func (c *Client) AttachmentMediaID(
+	ctx context.Context, attachmentID string,
+) (string, error) {
+	// the redirect is not followed
+	httpClient := *c.httpClient
+	httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	client := *c
+	client.httpClient = &httpClient
+
+	req, resp, err := client.performRequest(
+		ctx, "GET",
+		"/rest/api/3/attachment/content/"+url.PathEscape(attachmentID), nil,
+	)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		c.logFailedRequest(req, resp)
+		return "", fmt.Errorf(
+			"Failed to find the media of attachment %s: %s",
+			attachmentID, resp.Status,
+		)
+	}
+
+	matches := mediaFileRe.FindStringSubmatch(resp.Header.Get("Location"))
+	if matches == nil {
+		return "", fmt.Errorf(
+			"Failed to find the media of attachment %s: "+
+				"unexpected redirect to '%s'",
+			attachmentID, resp.Header.Get("Location"),
+		)
+	}
+	return matches[1], nil
+}
+
+/******************************************************************************
+ * Search JIRA Issues
+ *****************************************************************************/
+
+// FoundIssue is an issue returned by a JQL search.
+type FoundIssue struct {
+	Key         string
+	ProjectKey  string
+	Type        IssueType
+	Summary     string
+	Description *ADFNode
+	EpicKey     string
+	Labels      []string
+}
+
+type issSearchRespIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Project struct {
+			Key string `json:"key"`
+		} `json:"project"`
+		IssueType struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+		Summary     string   `json:"summary"`
+		Description *ADFNode `json:"description"`
+		Parent      *struct {
+			Key string `json:"key"`
+		} `json:"parent"`
+		Labels []string `json:"labels"`
+	} `json:"fields"`
+}
+
+type issSearchResp struct {
+	StartAt    int                  `json:"startAt"`
+	MaxResults int                  `json:"maxResults"`
+	Total      int                  `json:"total"`
+	Issues     []issSearchRespIssue `json:"issues"`
+}
+
+// searchPageSize is the number of issues requested from the JIRA search API
+// per page.
+const searchPageSize = 50
+
+var searchFields = []string{
+	"project", "issuetype", "summary", "description", "parent", "labels",
+}
+
+func (c *Client) SearchIssues(
+	ctx context.Context, jql string,
+) ([]*FoundIssue, error) {
+	foundIssues := []*FoundIssue{}
+	for {
+		respBody, err := c.searchIssuesPage(ctx, jql, len(foundIssues))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, respIssue := range respBody.Issues {
+			foundIssue := &FoundIssue{
+				Key:        respIssue.Key,
+				ProjectKey: respIssue.Fields.Project.Key,
+				Type: issueTypeFromName(
+					respIssue.Fields.IssueType.Name,
+				),
+				Summary:     respIssue.Fields.Summary,
+				Description: respIssue.Fields.Description,
+				Labels:      respIssue.Fields.Labels,
+			}
+			if respIssue.Fields.Parent != nil {
+				foundIssue.EpicKey = respIssue.Fields.Parent.Key
+			}
+			foundIssues = append(foundIssues, foundIssue)
+		}
+
+		if len(respBody.Issues) == 0 ||
+			len(foundIssues) >= respBody.Total {
+			break
+		}
+	}
+
+	return foundIssues, nil
+}
+
+// searchIssuesPage returns the page of the issues matching the JQL query
+// which starts at the index.
+func (c *Client) searchIssuesPage(
+	ctx context.Context, jql string, startAt int,
+) (*issSearchResp, error) {
+	query := url.Values{}
+	query.Set("jql", jql)
+	query.Set("startAt", strconv.Itoa(startAt))
+	query.Set("maxResults", strconv.Itoa(searchPageSize))
+	query.Set("fields", strings.Join(searchFields, ","))
+
+	req, resp, err := c.performRequest(
+		ctx, "GET", "/rest/api/3/search?"+query.Encode(), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		c.logFailedRequest(req, resp)
+		return nil, fmt.Errorf("Failed to search issues: %s", resp.Status)
+	}
+
+	respBody := &issSearchResp{}
+	if err := json.NewDecoder(resp.Body).Decode(respBody); err != nil {
+		return nil, fmt.Errorf("Failed to parse API response: %s", err)
+	}
+
+	return respBody, nil
+}
+
+/******************************************************************************
+ * Search JIRA Users
+ *****************************************************************************/
+
+// User is a user returned by a user search. The email address is empty
+// unless the privacy settings of the user allow it to be shown.
+type User struct {
+	AccountID    string `json:"accountId"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+// SearchUsers finds the users whose name or email address match the query.
+func (c *Client) SearchUsers(
+	ctx context.Context, query string,
+) ([]*User, error) {
+	params := url.Values{}
+	params.Set("query", query)
+
+	req, resp, err := c.performRequest(
+		ctx, "GET", "/rest/api/3/user/search?"+params.Encode(), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		c.logFailedRequest(req, resp)
+		return nil, fmt.Errorf("Failed to search users: %s", resp.Status)
+	}
+
+	users := []*User{}
+	if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+		return nil, fmt.Errorf("Failed to parse API response: %s", err)
+	}
+
+	return users, nil
+}
+
+/******************************************************************************
+ * JIRA Project Versions
+ *****************************************************************************/
+
+// Version is a version of a project, which issues can be fixed in.
+type Version struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ProjectVersions returns the versions of the project.
+func (c *Client) ProjectVersions(
+	ctx context.Context, projectKey string,
+) ([]*Version, error) {
+	req, resp, err := c.performRequest(
+		ctx, "GET",
+		"/rest/api/3/project/"+url.PathEscape(projectKey)+"/versions",
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		c.logFailedRequest(req, resp)
+		return nil, fmt.Errorf(
+			"Failed to list project versions: %s", resp.Status,
+		)
+	}
+
+	versions := []*Version{}
+	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
+		return nil, fmt.Errorf("Failed to parse API response: %s", err)
+	}
+
+	return versions, nil
+}
+
+type versionCreateReq struct {
+	Name    string `json:"name"`
+	Project string `json:"project"`
+}
+
+// CreateVersion creates a version of the project.
+func (c *Client) CreateVersion(
+	ctx context.Context, projectKey string, name string,
+) (*Version, error) {
+	reqBodyBytes, err := json.Marshal(versionCreateReq{
+		Name:    name,
+		Project: projectKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to serialize request body: %s", err)
+	}
+
+	req, resp, err := c.performRequest(
+		ctx, "POST", "/rest/api/3/version", reqBodyBytes,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 201 {
+		c.logFailedRequest(req, resp)
+		return nil, fmt.Errorf("Failed to create version: %s", resp.Status)
+	}
+
+	version := &Version{}
+	if err := json.NewDecoder(resp.Body).Decode(version); err != nil {
+		return nil, fmt.Errorf("Failed to parse API response: %s", err)
+	}
+
+	return version, nil
+}
+
 /******************************************************************************
  * Test JIRA API Connection
  *****************************************************************************/
 
-func (c *Client) Test() error {
-	req, resp, err := c.performRequest("GET", "/rest/api/3/project", nil)
+func (c *Client) Test(ctx context.Context) error {
+	req, resp, err := c.performRequest(
+		ctx, "GET", "/rest/api/3/project", nil,
+	)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
 		c.logFailedRequest(req, resp)
 		return fmt.Errorf(
@@ -209,25 +660,38 @@ func (c *Client) Test() error {
  *****************************************************************************/
 
 func (c *Client) performRequest(
-	method string, path string, body []byte,
+	ctx context.Context, method string, path string, body []byte,
+) (*http.Request, *http.Response, error) {
+	return c.performRequestWithBody(
+		ctx, method, path, "application/json", bytes.NewBuffer(body),
+	)
+}
+
+func (c *Client) performRequestWithBody(
+	ctx context.Context, method string, path string, contentType string,
+	body io.Reader,
 ) (*http.Request, *http.Response, error) {
 	reqURL := fmt.Sprintf(
 		"%s/%s",
 		strings.TrimRight(c.host, "/"),
 		strings.TrimLeft(path, "/"),
 	)
-	bodyFile := bytes.NewBuffer(body)
-	req, err := http.NewRequest(method, reqURL, bodyFile)
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
 	if err != nil {
 		return nil, nil, fmt.Errorf("Failed to create request: %s", err)
 	}
 	req.SetBasicAuth(c.username, c.token)
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("Accept", "application/json")
+	if strings.HasPrefix(contentType, "multipart/") {
+		// JIRA rejects multipart requests without it, as a protection
+		// against cross-site request forgery
+		req.Header.Set("X-Atlassian-Token", "no-check")
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, nil, fmt.Errorf("Failed to perform request: %s", err)
+		return nil, nil, fmt.Errorf("Failed to perform request: %w", err)
 	}
 
 	return req, resp, nil
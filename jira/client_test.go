@@ -0,0 +1,481 @@
+package jira_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glestaris/issuez/jira"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientImportIssuesChunks(t *testing.T) {
+	var chunkSizes []int
+	issueCount := 0
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/rest/api/3/issue/bulk", r.URL.Path)
+
+			var reqBody struct {
+				IssueUpdates []json.RawMessage `json:"issueUpdates"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+			chunkSizes = append(chunkSizes, len(reqBody.IssueUpdates))
+
+			respIssues := []map[string]string{}
+			for range reqBody.IssueUpdates {
+				issueCount++
+				respIssues = append(respIssues, map[string]string{
+					"key": fmt.Sprintf("TEST-%d", issueCount),
+				})
+			}
+			w.WriteHeader(201)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"issues": respIssues,
+				"errors": []interface{}{},
+			})
+		},
+	))
+	defer server.Close()
+
+	issues := make([]*jira.Issue, 120)
+	for i := range issues {
+		issues[i] = &jira.Issue{
+			ProjectKey: "TEST",
+			Type:       jira.IssueTypeTask,
+			Summary:    fmt.Sprintf("Issue %d", i+1),
+		}
+	}
+
+	client := jira.NewJiraClient(server.URL, "user", "token", nil)
+	resp, err := client.ImportIssues(context.Background(), issues)
+	require.NoError(t, err)
+	require.Equal(t, []int{50, 50, 20}, chunkSizes)
+	require.Len(t, resp, 120)
+	require.Equal(t, "TEST-1", resp[0].NewIssueKey)
+	require.Equal(t, "TEST-51", resp[50].NewIssueKey)
+	require.Equal(t, "TEST-120", resp[119].NewIssueKey)
+}
+
+func TestClientImportIssuesChunkErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var reqBody struct {
+				IssueUpdates []json.RawMessage `json:"issueUpdates"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+
+			// every chunk fails its first issue
+			respIssues := []map[string]string{}
+			for i := 1; i < len(reqBody.IssueUpdates); i++ {
+				respIssues = append(respIssues, map[string]string{
+					"key": "TEST-1",
+				})
+			}
+			w.WriteHeader(201)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"issues": respIssues,
+				"errors": []map[string]interface{}{
+					{"status": 400, "failedElementNumber": 0},
+				},
+			})
+		},
+	))
+	defer server.Close()
+
+	issues := make([]*jira.Issue, 60)
+	for i := range issues {
+		issues[i] = &jira.Issue{ProjectKey: "TEST", Summary: "Issue"}
+	}
+
+	client := jira.NewJiraClient(server.URL, "user", "token", nil)
+	resp, err := client.ImportIssues(context.Background(), issues)
+	require.NoError(t, err)
+	require.Len(t, resp, 60)
+	require.Error(t, resp[0].Err)
+	require.NoError(t, resp[1].Err)
+	require.Error(t, resp[50].Err)
+	require.NoError(t, resp[51].Err)
+	require.Equal(t, "TEST-1", resp[59].NewIssueKey)
+}
+
+func TestClientImportIssuesChunkFailure(t *testing.T) {
+	chunkCount := 0
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var reqBody struct {
+				IssueUpdates []json.RawMessage `json:"issueUpdates"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+			chunkCount++
+
+			// the second chunk fails as a whole
+			if chunkCount == 2 {
+				w.WriteHeader(500)
+				return
+			}
+
+			respIssues := []map[string]string{}
+			for i := range reqBody.IssueUpdates {
+				respIssues = append(respIssues, map[string]string{
+					"key": fmt.Sprintf("TEST-%d", i+1),
+				})
+			}
+			w.WriteHeader(201)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"issues": respIssues,
+				"errors": []interface{}{},
+			})
+		},
+	))
+	defer server.Close()
+
+	issues := make([]*jira.Issue, 120)
+	for i := range issues {
+		issues[i] = &jira.Issue{ProjectKey: "TEST", Summary: "Issue"}
+	}
+
+	client := jira.NewJiraClient(server.URL, "user", "token", nil)
+	resp, err := client.ImportIssues(context.Background(), issues)
+	require.EqualError(
+		t, err, "Failed to create issues: 500 Internal Server Error",
+	)
+	require.Equal(t, 2, chunkCount)
+	require.Len(t, resp, 50)
+	require.Equal(t, "TEST-1", resp[0].NewIssueKey)
+	require.Equal(t, "TEST-50", resp[49].NewIssueKey)
+}
+
+func TestClientImportIssuesCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	chunkCount := 0
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var reqBody struct {
+				IssueUpdates []json.RawMessage `json:"issueUpdates"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+			chunkCount++
+
+			// cancelled while the second chunk is created
+			if chunkCount == 2 {
+				cancel()
+				<-r.Context().Done()
+				return
+			}
+
+			respIssues := []map[string]string{}
+			for i := range reqBody.IssueUpdates {
+				respIssues = append(respIssues, map[string]string{
+					"key": fmt.Sprintf("TEST-%d", i+1),
+				})
+			}
+			w.WriteHeader(201)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"issues": respIssues,
+				"errors": []interface{}{},
+			})
+		},
+	))
+	defer server.Close()
+
+	issues := make([]*jira.Issue, 60)
+	for i := range issues {
+		issues[i] = &jira.Issue{
+			ProjectKey: "TEST",
+			Type:       jira.IssueTypeTask,
+			Summary:    fmt.Sprintf("Issue %d", i+1),
+		}
+	}
+
+	client := jira.NewJiraClient(server.URL, "user", "token", nil)
+	resp, err := client.ImportIssues(ctx, issues)
+	require.True(t, errors.Is(err, context.Canceled))
+	require.Equal(
+		t, 1, strings.Count(err.Error(), "Failed to perform request"),
+	)
+	require.Equal(t, 2, chunkCount)
+	require.Len(t, resp, 50)
+	require.Equal(t, "TEST-50", resp[49].NewIssueKey)
+}
+
+func TestClientRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		},
+	))
+	defer server.Close()
+
+	client := jira.NewJiraClient(
+		server.URL, "user", "token",
+		&http.Client{Timeout: 20 * time.Millisecond},
+	)
+	err := client.Test(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Client.Timeout exceeded")
+	require.Equal(
+		t, 1, strings.Count(err.Error(), "Failed to perform request"),
+	)
+}
+
+func TestClientSearchIssuesPaginates(t *testing.T) {
+	var startAts []string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/rest/api/3/search", r.URL.Path)
+			require.Equal(t, "project = TEST", r.URL.Query().Get("jql"))
+			startAt := r.URL.Query().Get("startAt")
+			startAts = append(startAts, startAt)
+
+			respIssues := []map[string]interface{}{}
+			first, count := 1, 50
+			if startAt == "50" {
+				first, count = 51, 10
+			}
+			for i := first; i < first+count; i++ {
+				respIssues = append(respIssues, map[string]interface{}{
+					"key": fmt.Sprintf("TEST-%d", i),
+					"fields": map[string]interface{}{
+						"project":   map[string]string{"key": "TEST"},
+						"issuetype": map[string]string{"name": "Bug"},
+						"summary":   fmt.Sprintf("Issue %d", i),
+						"parent":    map[string]string{"key": "TEST-100"},
+						"labels":    []string{"label-1"},
+						"description": map[string]interface{}{
+							"version": 1,
+							"type":    "doc",
+							"content": []interface{}{},
+						},
+					},
+				})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"startAt":    first - 1,
+				"maxResults": 50,
+				"total":      60,
+				"issues":     respIssues,
+			})
+		},
+	))
+	defer server.Close()
+
+	client := jira.NewJiraClient(server.URL, "user", "token", nil)
+	issues, err := client.SearchIssues(context.Background(), "project = TEST")
+	require.NoError(t, err)
+	require.Equal(t, []string{"0", "50"}, startAts)
+	require.Len(t, issues, 60)
+	require.Equal(t, "TEST-1", issues[0].Key)
+	require.Equal(t, "TEST-60", issues[59].Key)
+	require.Equal(t, jira.IssueTypeBug, issues[0].Type)
+	require.Equal(t, "Issue 1", issues[0].Summary)
+	require.Equal(t, "TEST-100", issues[0].EpicKey)
+	require.Equal(t, []string{"label-1"}, issues[0].Labels)
+	require.Equal(t, &jira.ADFNode{
+		Type:    "doc",
+		Content: []*jira.ADFNode{},
+		Extra:   map[string]json.RawMessage{"version": []byte("1")},
+	}, issues[0].Description)
+}
+
+func TestClientSearchIssuesError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(400)
+		},
+	))
+	defer server.Close()
+
+	client := jira.NewJiraClient(server.URL, "user", "token", nil)
+	_, err := client.SearchIssues(context.Background(), "project = ")
+	require.EqualError(t, err, "Failed to search issues: 400 Bad Request")
+}
+
+func TestClientAddAttachment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "POST", r.Method)
+			require.Equal(
+				t, "/rest/api/3/issue/TEST-1/attachments", r.URL.Path,
+			)
+			require.Equal(t, "no-check", r.Header.Get("X-Atlassian-Token"))
+
+			file, header, err := r.FormFile("file")
+			require.NoError(t, err)
+			defer file.Close()
+			content, err := ioutil.ReadAll(file)
+			require.NoError(t, err)
+			require.Equal(t, "bug.png", header.Filename)
+			require.Equal(t, "PNG", string(content))
+
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{
+					"id":       "10",
+					"filename": "bug.png",
+					"mimeType": "image/png",
+					"size":     3,
+					"content":  "https://e.com/attachment/content/10",
+				},
+			})
+		},
+	))
+	defer server.Close()
+
+	client := jira.NewJiraClient(server.URL, "user", "token", nil)
+	attachment, err := client.AddAttachment(
+		context.Background(),
+		"TEST-1", "bug.png", strings.NewReader("PNG"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, &jira.Attachment{
+		ID:         "10",
+		Filename:   "bug.png",
+		MimeType:   "image/png",
+		Size:       3,
+		ContentURL: "https://e.com/attachment/content/10",
+	}, attachment)
+}
+
+func TestClientAttachmentMediaID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/rest/api/3/attachment/content/10":
+				w.Header().Set(
+					"Location",
+					"https://api.media.atlassian.com/file/"+
+						"0a1b2c3d-1111/binary?token=abc",
+				)
+				w.WriteHeader(303)
+			default:
+				w.WriteHeader(404)
+			}
+		},
+	))
+	defer server.Close()
+
+	client := jira.NewJiraClient(server.URL, "user", "token", nil)
+	mediaID, err := client.AttachmentMediaID(context.Background(), "10")
+	require.NoError(t, err)
+	require.Equal(t, "0a1b2c3d-1111", mediaID)
+
+	_, err = client.AttachmentMediaID(context.Background(), "11")
+	require.EqualError(
+		t, err, "Failed to find the media of attachment 11: 404 Not Found",
+	)
+}
+
+func TestClientSearchUsers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "GET", r.Method)
+			require.Equal(t, "/rest/api/3/user/search", r.URL.Path)
+			require.Equal(t, "Alice Smith", r.URL.Query().Get("query"))
+
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{
+					"accountId":    "5b10ac8d82e05b22cc7d4ef5",
+					"accountType":  "atlassian",
+					"displayName":  "Alice Smith",
+					"emailAddress": "alice@example.com",
+				},
+			})
+		},
+	))
+	defer server.Close()
+
+	client := jira.NewJiraClient(server.URL, "user", "token", nil)
+	users, err := client.SearchUsers(context.Background(), "Alice Smith")
+	require.NoError(t, err)
+	require.Equal(t, []*jira.User{
+		{
+			AccountID:    "5b10ac8d82e05b22cc7d4ef5",
+			DisplayName:  "Alice Smith",
+			EmailAddress: "alice@example.com",
+		},
+	}, users)
+}
+
+func TestClientImportIssuesReleaseFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{
+				"issueUpdates": [{
+					"fields": {
+						"project": { "key": "TEST" },
+						"issuetype": { "name": "Release" },
+						"summary": "Alpha",
+						"parent": {},
+						"labels": null,
+						"fixVersions": [{ "name": "1.0" }]
+					}
+				}]
+			}`, string(body))
+
+			w.WriteHeader(201)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"issues": []map[string]string{{"key": "TEST-1"}},
+				"errors": []interface{}{},
+			})
+		},
+	))
+	defer server.Close()
+
+	client := jira.NewJiraClient(server.URL, "user", "token", nil)
+	resp, err := client.ImportIssues(context.Background(), []*jira.Issue{
+		{
+			ProjectKey:  "TEST",
+			Type:        jira.IssueTypeTask,
+			TypeName:    "Release",
+			Summary:     "Alpha",
+			FixVersions: []string{"1.0"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "TEST-1", resp[0].NewIssueKey)
+}
+
+func TestClientProjectVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "GET":
+				require.Equal(t, "/rest/api/3/project/TEST/versions", r.URL.Path)
+				json.NewEncoder(w).Encode([]map[string]interface{}{
+					{"id": "10000", "name": "1.0", "released": true},
+				})
+			case "POST":
+				require.Equal(t, "/rest/api/3/version", r.URL.Path)
+				body, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.JSONEq(
+					t, `{ "name": "2.0", "project": "TEST" }`, string(body),
+				)
+				w.WriteHeader(201)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"id": "10001", "name": "2.0",
+				})
+			}
+		},
+	))
+	defer server.Close()
+
+	client := jira.NewJiraClient(server.URL, "user", "token", nil)
+	versions, err := client.ProjectVersions(context.Background(), "TEST")
+	require.NoError(t, err)
+	require.Equal(t, []*jira.Version{{ID: "10000", Name: "1.0"}}, versions)
+
+	version, err := client.CreateVersion(context.Background(), "TEST", "2.0")
+	require.NoError(t, err)
+	require.Equal(t, &jira.Version{ID: "10001", Name: "2.0"}, version)
+}
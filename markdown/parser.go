@@ -0,0 +1,1588 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/russross/blackfriday/v2"
+	"github.com/glestaris/issuez/domain"
+)
+
+// LegacySeparator is the default separator of the issues of an import file.
+// Every horizontal rule separates issues, so descriptions cannot have rules.
+const LegacySeparator = "---"
+
+// SectionMode is the way the issues of an import file are found.
+type SectionMode int
+
+const (
+	// Issues are separated by the separator line
+	SectionModeSeparator SectionMode = iota
+	// Issues start at the headings of the heading level
+	SectionModeHeadings
+)
+
+// ParseSectionMode parses the name of a section mode: "separator" or
+// "headings".
+func ParseSectionMode(name string) (SectionMode, error) {
+	switch name {
+	case "separator":
+		return SectionModeSeparator, nil
+	case "headings":
+		return SectionModeHeadings, nil
+	default:
+		return SectionModeSeparator, fmt.Errorf(
+			"Invalid sections mode '%s'", name,
+		)
+	}
+}
+
+// DefaultIssueHeadingLevel is the level of the headings issues start at in
+// the headings section mode, e.g. "## [Bug] Title".
+const DefaultIssueHeadingLevel = 2
+
+// DefaultExtensions are the markdown extensions of the parser unless its
+// options set others.
+const DefaultExtensions = blackfriday.FencedCode | blackfriday.Strikethrough |
+	blackfriday.Tables | blackfriday.BackslashLineBreak | blackfriday.Autolink
+
+// ParserOptions configures how import files are parsed. The zero value
+// parses files whose issues are separated by horizontal rules.
+type ParserOptions struct {
+	// Extensions are the blackfriday extensions of the markdown, which
+	// default to DefaultExtensions.
+	Extensions blackfriday.Extensions
+
+	// Separator is the line which separates the issues of the file, e.g.
+	// "===" or "<!-- issue -->". Unless it is LegacySeparator, horizontal
+	// rules are kept in the descriptions.
+	Separator string
+
+	// Sections is the way the issues of the file are found. In the headings
+	// mode, each heading of the HeadingLevel (1 to 6, defaults to 2) starts
+	// an issue titled with its text. The closest heading of a lower level
+	// before an issue is the key of its epic. The separator is not used and
+	// horizontal rules are kept in the descriptions.
+	Sections     SectionMode
+	HeadingLevel int
+
+	// Prolific splits the file at every "---" line, as Prolific does, even
+	// right after a line of text. Otherwise, such a line turns the text into
+	// a heading, as in any markdown, unless an issue header follows it, which
+	// only happens in Prolific files. It is only used with LegacySeparator.
+	Prolific bool
+
+	// TypeAliases maps names of issue types, e.g. "Spike", to the types they
+	// stand for, on top of the names ParseIssueType knows.
+	TypeAliases map[string]domain.IssueType
+}
+
+// extensions returns the markdown extensions of the parser.
+func (o ParserOptions) extensions() blackfriday.Extensions {
+	if o.Extensions == blackfriday.NoExtensions {
+		return DefaultExtensions
+	}
+	return o.Extensions
+}
+
+// issueHeadingLevel returns the level of the headings issues start at.
+func (o ParserOptions) issueHeadingLevel() int {
+	if o.HeadingLevel == 0 {
+		return DefaultIssueHeadingLevel
+	}
+	return o.HeadingLevel
+}
+
+// isLegacy checks whether issues are separated by horizontal rules.
+func (o ParserOptions) isLegacy() bool {
+	return o.Sections == SectionModeSeparator &&
+		(o.Separator == "" || o.Separator == LegacySeparator)
+}
+
+// IssueError is the error of an issue of an import file which failed to
+// parse.
+type IssueError struct {
+	// Issue is the number of the issue, counted from 1
+	Issue int
+	// Line is the line of the file the issue starts at, or 0 if unknown
+	Line int
+	Err  error
+}
+
+func (e *IssueError) Error() string {
+	return fmt.Sprintf(
+		"Failed parsing issue %d in markdown file: %s", e.Issue, e.Err,
+	)
+}
+
+// ParseImportFile parses the issues of an import file whose issues are
+// separated by horizontal rules.
+func ParseImportFile(markdownFile io.Reader) ([]*domain.Issue, error) {
+	return ParseImportFileWithOptions(markdownFile, ParserOptions{})
+}
+
+// ParseImportFileWithOptions parses the issues of an import file.
+func ParseImportFileWithOptions(
+	markdownFile io.Reader, options ParserOptions,
+) ([]*domain.Issue, error) {
+	headingLevel := options.issueHeadingLevel()
+	if headingLevel < 1 || headingLevel > 6 {
+		return nil, fmt.Errorf("Invalid heading level %d", headingLevel)
+	}
+
+	data, err := ioutil.ReadAll(markdownFile)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read markdown file: %s", err)
+	}
+
+	// find sections
+	chunks := []chunk{{data: data, line: 1}}
+	if options.isLegacy() && (options.Prolific || isProlificLayout(data)) {
+		chunks = splitSeparatedChunks(data, LegacySeparator)
+	} else if !options.isLegacy() &&
+		options.Sections == SectionModeSeparator {
+		chunks = splitSeparatedChunks(data, options.Separator)
+	}
+	sections := []*section{}
+	for _, c := range chunks {
+		chunkSections, err := parseSections(c, options)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, chunkSections...)
+	}
+	if len(sections) == 0 {
+		return []*domain.Issue{}, nil
+	}
+
+	// create issues
+	issues := make([]*domain.Issue, len(sections))
+	for i, section := range sections {
+		issue, err := section.makeIssue(options.TypeAliases)
+		if err != nil {
+			return nil, &IssueError{
+				Issue: i + 1,
+				Line:  section.line,
+				Err:   err,
+			}
+		}
+		issues[i] = issue
+	}
+
+	return issues, nil
+}
+
+var fenceRe = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+
+// CodeFence follows the fenced code blocks of markdown, line by line.
+type CodeFence struct {
+	fence string
+}
+
+// Next moves to the line and returns whether it is part of a fenced code
+// block, fences included.
+func (f *CodeFence) Next(line string) bool {
+	if matches := fenceRe.FindStringSubmatch(line); matches != nil {
+		if f.fence == "" {
+			f.fence = matches[1]
+			return true
+		}
+		if matches[1][0] == f.fence[0] && len(matches[1]) >= len(f.fence) {
+			f.fence = ""
+			return true
+		}
+	}
+	return f.fence != ""
+}
+
+// prolificHeaderRe matches the lines which start issues with a type tag, e.g.
+// "[BUG] Sign up fails".
+var prolificHeaderRe = regexp.MustCompile(`^\[[^\[\]]+\]\s*\S`)
+
+// isProlificLayout checks whether the markdown is laid out as a Prolific
+// file: a "---" line right after a line of text, which would end a heading
+// in markdown, is followed by the header line of an issue.
+func isProlificLayout(data []byte) bool {
+	lines := strings.Split(string(data), "\n")
+	fence := CodeFence{}
+	afterText := false
+	for i, line := range lines {
+		inFence := fence.Next(line)
+		if !inFence && afterText && i < len(lines)-1 &&
+			strings.TrimRight(line, " \t\r") == LegacySeparator &&
+			prolificHeaderRe.MatchString(lines[i+1]) {
+			return true
+		}
+		afterText = !inFence && strings.TrimSpace(line) != ""
+	}
+	return false
+}
+
+// chunk is a part of an import file which is parsed on its own.
+type chunk struct {
+	data []byte
+	// line is the line of the file the chunk starts at, counted from 1
+	line int
+}
+
+// splitSeparatedChunks splits the markdown at the lines made only of the
+// separator. Lines in fenced code blocks do not split the markdown.
+func splitSeparatedChunks(data []byte, separator string) []chunk {
+	chunks := []chunk{}
+	lines := []string{}
+	line := 1
+	fence := CodeFence{}
+	for i, text := range strings.SplitAfter(string(data), "\n") {
+		inFence := fence.Next(text)
+		if !inFence && strings.TrimRight(text, " \t\r\n") == separator {
+			chunks = append(chunks, chunk{
+				data: []byte(strings.Join(lines, "")),
+				line: line,
+			})
+			lines = []string{}
+			// the chunk after the separator starts at the next line
+			line = i + 2
+			continue
+		}
+		lines = append(lines, text)
+	}
+	return append(chunks, chunk{
+		data: []byte(strings.Join(lines, "")),
+		line: line,
+	})
+}
+
+var (
+	hruleRe = regexp.MustCompile(
+		`^ {0,3}(?:(?:\* *){3,}|(?:- *){3,}|(?:_ *){3,})$`,
+	)
+	atxHeadingRe     = regexp.MustCompile(`^ {0,3}(#{1,6})`)
+	setextHeadingRe  = regexp.MustCompile(`^ {0,3}(?:=+|-+) *$`)
+	oneLineCommentRe = regexp.MustCompile(`^\s*<!--.*-->\s*$`)
+)
+
+// sectionLines finds the lines, counted from 1, the sections of the markdown
+// start at, in the way parseSections finds the sections. Markdown nodes do
+// not know their lines, so the lines are found by scanning the markdown for
+// the rules or headings which separate the sections.
+func sectionLines(data []byte, options ParserOptions) []int {
+	starts := []int{}
+	fence := CodeFence{}
+	prevText := false
+	afterRule := false
+	for i, text := range strings.Split(string(data), "\n") {
+		text = strings.TrimRight(text, " \t\r")
+		inFence := fence.Next(text)
+		isText := inFence ||
+			(text != "" && !oneLineCommentRe.MatchString(text))
+
+		switch {
+		case inFence:
+		case options.Sections == SectionModeHeadings:
+			matches := atxHeadingRe.FindStringSubmatch(text)
+			if matches != nil {
+				if len(matches[1]) == options.issueHeadingLevel() {
+					starts = append(starts, i+1)
+				}
+				isText = false
+			} else if prevText && setextHeadingRe.MatchString(text) {
+				level := 1
+				if strings.TrimSpace(text)[0] == '-' {
+					level = 2
+				}
+				// the heading is the line of text above the underline
+				if level == options.issueHeadingLevel() {
+					starts = append(starts, i)
+				}
+				isText = false
+			}
+		case options.isLegacy() && hruleRe.MatchString(text) &&
+			!(prevText && strings.TrimSpace(text)[0] == '-'):
+			afterRule = len(starts) > 0
+			isText = false
+		}
+
+		if isText && options.Sections != SectionModeHeadings &&
+			(len(starts) == 0 || afterRule) {
+			starts = append(starts, i+1)
+			afterRule = false
+		}
+		prevText = isText && !inFence
+	}
+	return starts
+}
+
+func parseMarkdown(data []byte, options ParserOptions) *blackfriday.Node {
+	md := blackfriday.New(blackfriday.WithExtensions(options.extensions()))
+	return md.Parse(data)
+}
+
+// ParseDocument parses markdown blocks, such as a description found in a
+// CSV or YAML file, to a document. Empty markdown has no document.
+func ParseDocument(
+	data []byte, options ParserOptions,
+) (*domain.Document, error) {
+	node := parseMarkdown(data, options)
+	if node == nil || node.FirstChild == nil {
+		return nil, nil
+	}
+	splitDetailsParagraphs(node)
+
+	domainDoc := &domain.Document{}
+	err := parseBlocks(node.FirstChild, node.LastChild, domainDoc)
+	if err != nil {
+		return nil, err
+	}
+	if len(domainDoc.Nodes) == 0 {
+		return nil, nil
+	}
+	return domainDoc, nil
+}
+
+// parseSections parses the chunk and finds the sections of its issues.
+func parseSections(c chunk, options ParserOptions) ([]*section, error) {
+	node := parseMarkdown(c.data, options)
+
+	// parsing did not produce a doc, no issues
+	if node == nil {
+		return []*section{}, nil
+	}
+	splitDetailsParagraphs(node)
+
+	// make document
+	doc, err := newDocument(node)
+	if err != nil {
+		log.Printf("Failed to create document: %s", err)
+		return nil, errors.New("Failed to parse markdown file")
+	}
+
+	var sections []*section
+	if options.Sections == SectionModeHeadings {
+		sections, err = doc.headingSections(options.issueHeadingLevel())
+	} else {
+		sections, err = doc.sections(options.isLegacy())
+	}
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Failed to extract issues from markdown file: %s", err,
+		)
+	}
+
+	// the lines are unknown if the scan does not find the same sections
+	lines := sectionLines(c.data, options)
+	if len(lines) == len(sections) {
+		for i, section := range sections {
+			section.line = c.line + lines[i] - 1
+		}
+	}
+	return sections, nil
+}
+
+type document struct {
+	root *blackfriday.Node
+}
+
+func newDocument(doc *blackfriday.Node) (*document, error) {
+	if doc.Type != blackfriday.Document {
+		return nil, errors.New("Document not found")
+	}
+	return &document{root: doc}, nil
+}
+
+func isNodeEmpty(node *blackfriday.Node) bool {
+	// nil node is empty
+	if node == nil {
+		return true
+	}
+
+	// images are not empty, even without alt text, and neither are rules
+	if node.Type == blackfriday.Image ||
+		node.Type == blackfriday.HorizontalRule {
+		return false
+	}
+
+	// doesn't have children: empty if literal is empty
+	if node.FirstChild == nil {
+		return len(node.Literal) == 0
+	}
+
+	// has children: empty if all children are empty
+	foundNonEmpty := false
+	node.Walk(func(n *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if !entering {
+			return blackfriday.GoToNext
+		}
+
+		if n == node {
+			return blackfriday.GoToNext
+		}
+		if !isNodeEmpty(n) {
+			foundNonEmpty = true
+			return blackfriday.Terminate
+		}
+		return blackfriday.SkipChildren
+	})
+	return !foundNonEmpty
+}
+
+func isHTMLComment(node *blackfriday.Node) bool {
+	if node.Type != blackfriday.HTMLBlock {
+		return false
+	}
+	literal := strings.TrimSpace(string(node.Literal))
+	return strings.HasPrefix(literal, "<!--") && strings.HasSuffix(literal, "-->")
+}
+
+func (d *document) sections(rulesSeparate bool) ([]*section, error) {
+	boundaries := []*blackfriday.Node{}
+	currNode := d.root.FirstChild
+	for currNode != nil {
+		// remove HRs
+		if rulesSeparate && currNode.Type == blackfriday.HorizontalRule {
+			// mark prev as boundary
+			// IFF
+			//  1) prev != nil: not the first node
+			//  2) next != nil: not the last node
+			if currNode.Prev != nil && currNode.Next != nil {
+				b := currNode.Prev
+				// boundary does not already exist in list
+				if len(boundaries) == 0 ||
+					boundaries[len(boundaries)-1] != b {
+					boundaries = append(boundaries, b)
+				}
+			}
+
+			nextNode := currNode.Next
+			currNode.Unlink()
+			currNode = nextNode
+			continue
+		}
+
+		// remove empty nodes and comments
+		if isNodeEmpty(currNode) || isHTMLComment(currNode) {
+			nextNode := currNode.Next
+			currNode.Unlink()
+			currNode = nextNode
+			continue
+		}
+
+		currNode = currNode.Next
+	}
+
+	if d.root.FirstChild == nil {
+		return []*section{}, nil
+	}
+
+	var sections []*section
+	// no boundaries? - single issue
+	if len(boundaries) == 0 {
+		sections = []*section{
+			{
+				firstNode: d.root.FirstChild,
+				lastNode:  d.root.LastChild,
+			},
+		}
+	} else {
+		sections = make([]*section, len(boundaries)+1)
+		// first section
+		sections[0] = &section{
+			firstNode: d.root.FirstChild,
+			lastNode:  boundaries[0],
+		}
+		// sections in between
+		for sectionIdx := 1; sectionIdx < len(boundaries); sectionIdx++ {
+			sections[sectionIdx] = &section{
+				firstNode: boundaries[sectionIdx-1].Next,
+				lastNode:  boundaries[sectionIdx],
+			}
+		}
+		// last section
+		sections[len(boundaries)] = &section{
+			firstNode: boundaries[len(boundaries)-1].Next,
+			lastNode:  d.root.LastChild,
+		}
+	}
+
+	return sections, nil
+}
+
+// issueKeyRe matches the issue keys in the text of headings, e.g. PROJ-12.
+var issueKeyRe = regexp.MustCompile(`\b[A-Z][A-Z0-9_]*-[0-9]+\b`)
+
+// headingSections finds the sections which start at the headings of the
+// level. Headings of a lower level end the section before them and set the
+// epic of the sections after them to the issue key they contain, or to no
+// epic if they contain none. Blocks before the first section are not part of
+// any issue.
+func (d *document) headingSections(level int) ([]*section, error) {
+	sections := []*section{}
+	var currSection *section
+	epicID := ""
+	currNode := d.root.FirstChild
+	for currNode != nil {
+		// remove empty nodes and comments
+		if isNodeEmpty(currNode) || isHTMLComment(currNode) {
+			nextNode := currNode.Next
+			currNode.Unlink()
+			currNode = nextNode
+			continue
+		}
+
+		if currNode.Type == blackfriday.Heading &&
+			currNode.HeadingData.Level <= level {
+			if currSection != nil {
+				currSection.lastNode = currNode.Prev
+				currSection = nil
+			}
+			if currNode.HeadingData.Level < level {
+				epicID = issueKeyRe.FindString(parseText(currNode))
+			} else {
+				currSection = &section{firstNode: currNode, epicID: epicID}
+				sections = append(sections, currSection)
+			}
+		}
+
+		currNode = currNode.Next
+	}
+	if currSection != nil {
+		currSection.lastNode = d.root.LastChild
+	}
+
+	return sections, nil
+}
+
+type section struct {
+	firstNode *blackfriday.Node
+	lastNode  *blackfriday.Node
+
+	// epicID is the epic of the issue, unless its footer has one
+	epicID string
+	// line is the line of the file the section starts at, or 0 if unknown
+	line int
+}
+
+func (s *section) makeIssue(
+	typeAliases map[string]domain.IssueType,
+) (*domain.Issue, error) {
+	s.splitHeaderLine()
+	s.splitFooterLines()
+
+	// parse header
+	issueType, title, err := s.parseHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	// parse footer
+	epicID, labels, attachments := s.parseFooter()
+
+	// parse description
+	var incLastNode bool
+	if epicID == "" && labels == nil && attachments == nil {
+		// last node was not used as footer
+		incLastNode = true
+	}
+	description, err := s.parseDescription(incLastNode)
+	if err != nil {
+		return nil, err
+	}
+
+	// make issue
+	issue := &domain.Issue{}
+
+	// issue title
+	issue.Title = title
+
+	// issue type
+	issue.Type, err = ParseIssueType(issueType, typeAliases)
+	if err != nil {
+		return nil, err
+	}
+
+	// issue description
+	issue.Description = description
+
+	// issue epic
+	if epicID == "" {
+		epicID = s.epicID
+	}
+	if epicID != "" {
+		issue.Epic = &domain.Epic{ID: epicID}
+	}
+
+	// issue labels
+	if labels != nil && len(labels) != 0 {
+		for _, label := range labels {
+			issue.Labels = append(issue.Labels, domain.Label{
+				Label: label,
+			})
+		}
+	}
+
+	// issue attachments
+	for _, attachment := range attachments {
+		issue.Attachments = append(issue.Attachments, domain.Attachment{
+			Path: attachment,
+		})
+	}
+
+	return issue, nil
+}
+
+// isTextParagraph checks whether the node is a paragraph, or a heading, made
+// of text only. Escaped characters split the text of a paragraph in more
+// than one node.
+func isTextParagraph(node *blackfriday.Node) bool {
+	if (node.Type != blackfriday.Paragraph &&
+		node.Type != blackfriday.Heading) || node.FirstChild == nil {
+		return false
+	}
+	for child := node.FirstChild; child != nil; child = child.Next {
+		if child.Type != blackfriday.Text {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseIssueType parses the name of an issue type, or of one of the type
+// aliases. Names are case-insensitive, as in Prolific files, and issues are
+// stories unless they name another type.
+func ParseIssueType(
+	name string, aliases map[string]domain.IssueType,
+) (domain.IssueType, error) {
+	name = strings.TrimSpace(name)
+	for alias, issueType := range aliases {
+		if strings.EqualFold(alias, name) {
+			return issueType, nil
+		}
+	}
+	switch strings.ToLower(name) {
+	case "", "story", "issue", "feature":
+		return domain.IssueTypeStory, nil
+	case "bug":
+		return domain.IssueTypeBug, nil
+	case "chore", "task":
+		return domain.IssueTypeChore, nil
+	case "release":
+		return domain.IssueTypeRelease, nil
+	default:
+		return domain.IssueTypeStory, fmt.Errorf(
+			"Unknown issue type %s", name,
+		)
+	}
+}
+
+// splitParagraph moves the text of the paragraph which follows the offset in
+// its text child to a new paragraph after it.
+func splitParagraph(
+	paragraph *blackfriday.Node, text *blackfriday.Node, offset int,
+) *blackfriday.Node {
+	restText := blackfriday.NewNode(blackfriday.Text)
+	restText.Literal = append([]byte{}, text.Literal[offset:]...)
+	text.Literal = text.Literal[:offset]
+
+	rest := blackfriday.NewNode(blackfriday.Paragraph)
+	rest.AppendChild(restText)
+	for child := text.Next; child != nil; child = text.Next {
+		rest.AppendChild(child)
+	}
+	if paragraph.Next != nil {
+		paragraph.Next.InsertBefore(rest)
+	} else {
+		paragraph.Parent.AppendChild(rest)
+	}
+	return rest
+}
+
+// splitHeaderLine moves the lines which follow the first line of the section
+// to a paragraph of their own. Prolific files start descriptions right
+// after the title line.
+func (s *section) splitHeaderLine() {
+	f := s.firstNode
+	if f.Type != blackfriday.Paragraph {
+		return
+	}
+	for child := f.FirstChild; child != nil; child = child.Next {
+		if child.Type != blackfriday.Text {
+			return
+		}
+		offset := strings.IndexByte(string(child.Literal), '\n')
+		if offset == -1 {
+			continue
+		}
+		if strings.TrimSpace(string(child.Literal[offset:])) == "" &&
+			child.Next == nil {
+			return
+		}
+		rest := splitParagraph(f, child, offset+1)
+		if s.lastNode == f {
+			s.lastNode = rest
+		}
+		return
+	}
+}
+
+// footerLineRe matches the lines of the footer of a section.
+var footerLineRe = regexp.MustCompile(`^\s*(?:E|Epic|L|Labels|Attach):`)
+
+// splitFooterLines moves the footer lines which end the last paragraph of
+// the section to a paragraph of their own. Prolific files end descriptions
+// with a labels line.
+func (s *section) splitFooterLines() {
+	l := s.lastNode
+	if l == s.firstNode || l.Type != blackfriday.Paragraph ||
+		l.LastChild == nil || l.LastChild.Type != blackfriday.Text {
+		return
+	}
+	text := l.LastChild
+	lines := strings.Split(string(text.Literal), "\n")
+	footerStart := len(lines)
+	for footerStart > 0 && (footerLineRe.MatchString(lines[footerStart-1]) ||
+		strings.TrimSpace(lines[footerStart-1]) == "") {
+		footerStart--
+	}
+	if footerStart == 0 && text == l.FirstChild {
+		// the paragraph is the footer
+		return
+	}
+	if footerStart == 0 {
+		// the first line of the text continues the line before it
+		footerStart = 1
+	}
+
+	hasFooter := false
+	for _, line := range lines[footerStart:] {
+		hasFooter = hasFooter || footerLineRe.MatchString(line)
+	}
+	if !hasFooter {
+		return
+	}
+	offset := len(strings.Join(lines[:footerStart], "\n")) + 1
+	s.lastNode = splitParagraph(l, text, offset)
+}
+
+func (s *section) parseHeader() (string, string, error) {
+	f := s.firstNode
+	if !isTextParagraph(f) {
+		return "", "", errors.New(
+			"First line in issue section needs to be of the form" +
+				" '[ISSUE TYPE] ISSUE TITLE'",
+		)
+	}
+	firstLine := parseText(f)
+
+	re := regexp.MustCompile(`^\s*(?:\[([^\[\]]+)\])?\s*(.+)\s*$`)
+	matches := re.FindStringSubmatch(firstLine)
+	if len(matches) == 2 {
+		return "", strings.TrimSpace(matches[1]), nil
+	}
+	if len(matches) == 3 {
+		return strings.TrimSpace(matches[1]),
+			strings.TrimSpace(matches[2]),
+			nil
+	}
+
+	return "", "", errors.New(
+		"First line in issue section needs to be of the form" +
+			" '[ISSUE TYPE] ISSUE TITLE'",
+	)
+}
+
+func (s *section) parseFooter() (string, []string, []string) {
+	l := s.lastNode
+	if l.Type != blackfriday.Paragraph ||
+		l.FirstChild == nil ||
+		l.FirstChild != l.LastChild ||
+		l.FirstChild.Type != blackfriday.Text {
+		return "", nil, nil
+	}
+	lastParagraph := string(l.FirstChild.Literal)
+
+	var epicID string
+	epicRe := regexp.MustCompile(`(?:E|Epic):\s*(.+)`)
+	epicReMatches := epicRe.FindStringSubmatch(lastParagraph)
+	if len(epicReMatches) == 2 {
+		epicID = strings.TrimSpace(epicReMatches[1])
+	}
+
+	var labels []string
+	labelsRe := regexp.MustCompile(`(?:L|Labels):\s*(.+)`)
+	labelsReMatches := labelsRe.FindStringSubmatch(lastParagraph)
+	if len(labelsReMatches) == 2 {
+		labels = []string{}
+		for _, label := range strings.Split(labelsReMatches[1], ",") {
+			labels = append(labels, strings.TrimSpace(label))
+		}
+	}
+
+	var attachments []string
+	attachRe := regexp.MustCompile(`Attach:\s*(.+)`)
+	attachReMatches := attachRe.FindStringSubmatch(lastParagraph)
+	if len(attachReMatches) == 2 {
+		attachments = []string{}
+		for _, path := range strings.Split(attachReMatches[1], ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				attachments = append(attachments, path)
+			}
+		}
+	}
+
+	return epicID, labels, attachments
+}
+
+// htmlColorRe matches the opening tag of a span which sets the colour of its
+// text, e.g. `<span style="color: #ff5630">`.
+var htmlColorRe = regexp.MustCompile(
+	`^<span\s+style="color:\s*(#[0-9a-fA-F]{6})\s*;?\s*">$`,
+)
+
+// textParser adds text to a container, keeping the formatting of the text
+// across the nodes it parses.
+type textParser struct {
+	textMode    domain.TextMode
+	linkURL     string
+	isImageLink bool
+
+	// spanColors are the colours of the open HTML spans
+	spanColors []string
+}
+
+// parseHTMLSpan updates the mode of the text that follows an inline HTML
+// tag. The tags supported are <u>, <sub>, <sup> and <span>s setting the
+// colour of the text, others are ignored.
+func (p *textParser) parseHTMLSpan(tag string) {
+	switch strings.ToLower(tag) {
+	case "<u>":
+		p.textMode.Underline = true
+	case "</u>":
+		p.textMode.Underline = false
+	case "<sub>":
+		p.textMode.Subscript = true
+	case "</sub>":
+		p.textMode.Subscript = false
+	case "<sup>":
+		p.textMode.Superscript = true
+	case "</sup>":
+		p.textMode.Superscript = false
+	case "</span>":
+		if len(p.spanColors) != 0 {
+			p.spanColors = p.spanColors[:len(p.spanColors)-1]
+		}
+		p.textMode.Color = ""
+		if len(p.spanColors) != 0 {
+			p.textMode.Color = p.spanColors[len(p.spanColors)-1]
+		}
+	default:
+		if matches := htmlColorRe.FindStringSubmatch(tag); matches != nil {
+			p.textMode.Color = strings.ToLower(matches[1])
+		}
+		if strings.HasPrefix(strings.ToLower(tag), "<span") {
+			// spans without a colour keep the colour of the text
+			p.spanColors = append(p.spanColors, p.textMode.Color)
+		}
+	}
+}
+
+func (p *textParser) addText(text string, tc *domain.TextContainer) {
+	if p.linkURL == "" {
+		tc.AddText(text, p.textMode)
+	} else {
+		tc.AddLink(text, p.linkURL, p.textMode)
+	}
+}
+
+// inlineNodeRe matches the mentions (`@[Alice Smith]` or
+// `@alice@example.com`), runs of emojis (`:warning:` or `:+1::tada:`) and
+// dates (`{date:2026-11-01}`) written in text.
+var inlineNodeRe = regexp.MustCompile(
+	`@\[([^\[\]\n]+)\]` +
+		`|@([\w.%+-]+@[\w-]+(?:\.[\w-]+)*\.[A-Za-z]{2,})\b` +
+		`|((?::[a-z0-9_+-]+:)+)` +
+		`|\{date:(\d{4}-\d{2}-\d{2})\}`,
+)
+
+// addInlineNodes adds the text to the container, turning the mentions,
+// emojis and dates written in it into elements of their own. Emojis are only
+// recognised apart from words and colons, so that times like 12:30:45 and
+// names like std::vector::push are kept as they are.
+func (p *textParser) addInlineNodes(
+	text string, tc *domain.TextContainer,
+) {
+	start := 0
+	for _, match := range inlineNodeRe.FindAllStringSubmatchIndex(text, -1) {
+		group := func(i int) string {
+			if match[2*i] < 0 {
+				return ""
+			}
+			return text[match[2*i]:match[2*i+1]]
+		}
+
+		mention := strings.TrimSpace(group(1))
+		if mention == "" {
+			mention = group(2)
+		}
+		emojis := group(3)
+		if emojis != "" && (isEmojiNeighbour(lastRune(text[:match[0]])) ||
+			isEmojiNeighbour(firstRune(text[match[1]:]))) {
+			emojis = ""
+		}
+		date := group(4)
+		if date != "" && !isDate(date) {
+			date = ""
+		}
+		if mention == "" && emojis == "" && date == "" {
+			continue
+		}
+
+		if match[0] > start {
+			tc.AddText(text[start:match[0]], p.textMode)
+		}
+		switch {
+		case mention != "":
+			tc.AddMention(mention, "")
+		case emojis != "":
+			for _, emoji := range strings.Split(emojis[1:len(emojis)-1], "::") {
+				tc.AddEmoji(emoji)
+			}
+		default:
+			tc.AddDate(date)
+		}
+		start = match[1]
+	}
+	if start < len(text) {
+		tc.AddText(text[start:], p.textMode)
+	}
+}
+
+// isEmojiNeighbour checks whether the rune next to an emoji makes it part of
+// other text: a letter, a digit, an underscore or a colon.
+func isEmojiNeighbour(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == ':'
+}
+
+func firstRune(text string) rune {
+	r, _ := utf8.DecodeRuneInString(text)
+	return r
+}
+
+func lastRune(text string) rune {
+	r, _ := utf8.DecodeLastRuneInString(text)
+	return r
+}
+
+func isDate(date string) bool {
+	_, err := time.Parse("2006-01-02", date)
+	return err == nil
+}
+
+// parse adds the text of the node to the container. Images in text are
+// added as links to them, unless they are already in a link.
+func (p *textParser) parse(node *blackfriday.Node, tc *domain.TextContainer) {
+	node.Walk(func(
+		in *blackfriday.Node, entering bool,
+	) blackfriday.WalkStatus {
+		switch in.Type {
+		case blackfriday.Strong:
+			p.textMode.Bold = !p.textMode.Bold
+		case blackfriday.Emph:
+			p.textMode.Italics = !p.textMode.Italics
+		case blackfriday.Del:
+			p.textMode.Strikethrough = !p.textMode.Strikethrough
+		case blackfriday.Link:
+			if entering {
+				p.linkURL = string(in.LinkData.Destination)
+			} else {
+				p.linkURL = ""
+			}
+		case blackfriday.Image:
+			if entering && p.linkURL == "" {
+				p.linkURL = string(in.LinkData.Destination)
+				p.isImageLink = true
+			} else if !entering && p.isImageLink {
+				p.linkURL = ""
+				p.isImageLink = false
+			}
+
+			// Leafs
+		case blackfriday.HTMLSpan:
+			p.parseHTMLSpan(string(in.Literal))
+		case blackfriday.Hardbreak:
+			tc.AddHardBreak()
+		case blackfriday.Code:
+			if len(in.Literal) == 0 {
+				return blackfriday.GoToNext
+			}
+
+			p.textMode.Code = true
+			p.addText(string(in.Literal), tc)
+			p.textMode.Code = false
+		case blackfriday.Text:
+			if len(in.Literal) == 0 {
+				return blackfriday.GoToNext
+			}
+
+			if p.linkURL != "" {
+				p.addText(string(in.Literal), tc)
+			} else {
+				p.addInlineNodes(string(in.Literal), tc)
+			}
+		}
+
+		return blackfriday.GoToNext
+	})
+}
+
+// parseTextContainer adds the text of the node to the container.
+func parseTextContainer(node *blackfriday.Node, tc *domain.TextContainer) {
+	parser := textParser{}
+	parser.parse(node, tc)
+}
+
+// trimTextContainer removes the whitespace at the start and the end of the
+// text, unless it is code.
+func trimTextContainer(tc *domain.TextContainer) {
+	for len(tc.Elements) != 0 && !tc.Elements[0].Mode.Code {
+		first := &tc.Elements[0]
+		first.Text = strings.TrimLeft(first.Text, " \t\n")
+		if first.Text != "" {
+			break
+		}
+		tc.Elements = tc.Elements[1:]
+	}
+	for len(tc.Elements) != 0 && !tc.Elements[len(tc.Elements)-1].Mode.Code {
+		last := &tc.Elements[len(tc.Elements)-1]
+		last.Text = strings.TrimRight(last.Text, " \t\n")
+		if last.Text != "" {
+			break
+		}
+		tc.Elements = tc.Elements[:len(tc.Elements)-1]
+	}
+}
+
+// escapedCharRe matches the punctuation characters escaped with a
+// backslash.
+var escapedCharRe = regexp.MustCompile("\\\\([!-/:-@\\[-`{-~])")
+
+// parseAltText returns the alt text of an image. The parser keeps the alt
+// text as it is written, escapes included.
+func parseAltText(node *blackfriday.Node) string {
+	return escapedCharRe.ReplaceAllString(parseText(node), "$1")
+}
+
+// parseParagraph adds the paragraph to the document. The images of the
+// paragraph are added as blocks of their own, splitting the text around
+// them in paragraphs.
+func parseParagraph(node *blackfriday.Node, domainDoc *domain.Document) {
+	tc := domain.TextContainer{}
+	addText := func() {
+		trimTextContainer(&tc)
+		if len(tc.Elements) != 0 {
+			*domainDoc.AddParagraph() = tc
+		}
+		tc = domain.TextContainer{}
+	}
+
+	// the formatting set by HTML tags spans over the children
+	parser := textParser{}
+	for child := node.FirstChild; child != nil; child = child.Next {
+		if child.Type == blackfriday.Image {
+			addText()
+			domainDoc.AddImage(
+				string(child.LinkData.Destination), parseAltText(child),
+			)
+			continue
+		}
+		parser.parse(child, &tc)
+	}
+	addText()
+}
+
+func parseText(node *blackfriday.Node) string {
+	text := ""
+	node.Walk(func(
+		in *blackfriday.Node, entering bool,
+	) blackfriday.WalkStatus {
+		text += string(in.Literal)
+		return blackfriday.GoToNext
+	})
+	return text
+}
+
+func headingLevel(nodeHeadingLevel int) domain.HeadingLevel {
+	switch nodeHeadingLevel {
+	case 1:
+		return domain.HeadingLevel1
+	case 2:
+		return domain.HeadingLevel2
+	case 3:
+		return domain.HeadingLevel3
+	case 4:
+		return domain.HeadingLevel4
+	case 5:
+		return domain.HeadingLevel5
+	default:
+		return domain.HeadingLevel5
+	}
+}
+
+// taskMarkerRe matches the checkbox the items of a task list start with, e.g.
+// "[ ] " or "[x] ".
+var taskMarkerRe = regexp.MustCompile(`^\[([ xX])\](?: |$)`)
+
+// isTaskList checks whether every item of the list starts with a checkbox.
+func isTaskList(node *blackfriday.Node) bool {
+	for in := node.FirstChild; in != nil; in = in.Next {
+		p := in.FirstChild
+		if p == nil || p.Type != blackfriday.Paragraph ||
+			p.FirstChild == nil || p.FirstChild.Type != blackfriday.Text ||
+			!taskMarkerRe.Match(p.FirstChild.Literal) {
+			return false
+		}
+	}
+	return node.FirstChild != nil
+}
+
+// parseTaskMarker removes the checkbox from the text of a task list item and
+// returns whether it is checked.
+func parseTaskMarker(in *blackfriday.Node) bool {
+	text := in.FirstChild.FirstChild
+	matches := taskMarkerRe.FindSubmatch(text.Literal)
+	text.Literal = text.Literal[len(matches[0]):]
+	return string(matches[1]) != " "
+}
+
+// parseList parses the items of a list. The first paragraph of an item is its
+// text and the blocks that follow are nested in it.
+func parseList(node *blackfriday.Node, list *domain.ListData) error {
+	for in := node.FirstChild; in != nil; in = in.Next {
+		item := list.AddItem()
+		if list.IsTaskList {
+			item.IsChecked = parseTaskMarker(in)
+		}
+		blockNode := in.FirstChild
+		if blockNode != nil && blockNode.Type == blackfriday.Paragraph &&
+			!isDetailsStart(blockNode) {
+			parseTextContainer(blockNode, &item.TextContainer)
+			blockNode = blockNode.Next
+		}
+		if blockNode == nil {
+			continue
+		}
+		if err := parseBlocks(blockNode, in.LastChild, &item.Document); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// calloutRe matches the marker a GitHub callout quote starts with, e.g.
+// "[!NOTE]".
+var calloutRe = regexp.MustCompile(
+	`^\[!(?i)(NOTE|TIP|IMPORTANT|WARNING|CAUTION)\][ \t]*\n?`,
+)
+
+var calloutPanelTypes = map[string]domain.PanelType{
+	"NOTE":      domain.PanelTypeInfo,
+	"TIP":       domain.PanelTypeSuccess,
+	"IMPORTANT": domain.PanelTypeNote,
+	"WARNING":   domain.PanelTypeWarning,
+	"CAUTION":   domain.PanelTypeError,
+}
+
+// parseCallout checks whether the quote is a callout and removes the callout
+// marker from it.
+func parseCallout(node *blackfriday.Node) (domain.PanelType, bool) {
+	p := node.FirstChild
+	if p == nil || p.Type != blackfriday.Paragraph ||
+		p.FirstChild == nil || p.FirstChild.Type != blackfriday.Text {
+		return 0, false
+	}
+	text := p.FirstChild
+	matches := calloutRe.FindSubmatch(text.Literal)
+	if matches == nil {
+		return 0, false
+	}
+
+	text.Literal = text.Literal[len(matches[0]):]
+	if isNodeEmpty(p) {
+		// the marker was on a paragraph of its own
+		p.Unlink()
+	}
+	return calloutPanelTypes[strings.ToUpper(string(matches[1]))], true
+}
+
+// detailsStartRe, detailsEndRe, summaryStartRe and summaryEndRe match the
+// HTML tags of <details> blocks, which are read as expands.
+var (
+	detailsStartRe = regexp.MustCompile(`(?i)^<details(?:\s[^>]*)?>$`)
+	detailsEndRe   = regexp.MustCompile(`(?i)^</details\s*>$`)
+	summaryStartRe = regexp.MustCompile(`(?i)^<summary(?:\s[^>]*)?>$`)
+	summaryEndRe   = regexp.MustCompile(`(?i)^</summary\s*>$`)
+)
+
+func isHTMLTag(node *blackfriday.Node, tagRe *regexp.Regexp) bool {
+	return node.Type == blackfriday.HTMLSpan && tagRe.Match(node.Literal)
+}
+
+func isBlankText(node *blackfriday.Node) bool {
+	return node.Type == blackfriday.Text &&
+		strings.TrimSpace(string(node.Literal)) == ""
+}
+
+// splitDetailsParagraphs splits the paragraphs of the document at the tags
+// of <details> blocks. The tags are inline HTML to the markdown parser, so
+// they end up in paragraphs with the text around them. Once split, the
+// opening tag and the summary make a paragraph of their own, and so does
+// the closing tag. A summary can also start the paragraph after the opening
+// tag, as GitHub allows a blank line between them.
+func splitDetailsParagraphs(root *blackfriday.Node) {
+	paragraphs := []*blackfriday.Node{}
+	root.Walk(func(
+		in *blackfriday.Node, entering bool,
+	) blackfriday.WalkStatus {
+		if entering && in.Type == blackfriday.Paragraph {
+			paragraphs = append(paragraphs, in)
+			return blackfriday.SkipChildren
+		}
+		return blackfriday.GoToNext
+	})
+	for _, paragraph := range paragraphs {
+		splitDetailsParagraph(paragraph)
+	}
+
+	for _, paragraph := range paragraphs {
+		// the paragraph is the last piece it was split into, so the opening
+		// tag is the paragraph before it
+		details := paragraph.Prev
+		if details == nil || !isDetailsStart(details) ||
+			hasSummary(details) {
+			continue
+		}
+		child := firstNonBlankChild(paragraph)
+		if child == nil || !isHTMLTag(child, summaryStartRe) {
+			continue
+		}
+
+		for child := paragraph.FirstChild; child != nil; {
+			next := child.Next
+			details.AppendChild(child)
+			child = next
+		}
+		paragraph.Unlink()
+		splitDetailsParagraph(details)
+	}
+}
+
+func hasSummary(paragraph *blackfriday.Node) bool {
+	for child := paragraph.FirstChild; child != nil; child = child.Next {
+		if isHTMLTag(child, summaryStartRe) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitDetailsParagraph(paragraph *blackfriday.Node) {
+	const (
+		inText = iota
+		afterDetailsStart
+		inSummary
+	)
+
+	pieces := [][]*blackfriday.Node{{}}
+	newPiece := func() {
+		if len(pieces[len(pieces)-1]) != 0 {
+			pieces = append(pieces, []*blackfriday.Node{})
+		}
+	}
+	addToPiece := func(node *blackfriday.Node) {
+		pieces[len(pieces)-1] = append(pieces[len(pieces)-1], node)
+	}
+
+	state := inText
+	for child := paragraph.FirstChild; child != nil; child = child.Next {
+		switch {
+		case state == afterDetailsStart && isBlankText(child):
+			addToPiece(child)
+			continue
+		case state == afterDetailsStart && isHTMLTag(child, summaryStartRe):
+			addToPiece(child)
+			state = inSummary
+			continue
+		case state == afterDetailsStart:
+			// no summary
+			newPiece()
+			state = inText
+		case state == inSummary:
+			addToPiece(child)
+			if isHTMLTag(child, summaryEndRe) {
+				newPiece()
+				state = inText
+			}
+			continue
+		}
+
+		switch {
+		case isHTMLTag(child, detailsStartRe):
+			newPiece()
+			addToPiece(child)
+			state = afterDetailsStart
+		case isHTMLTag(child, detailsEndRe):
+			newPiece()
+			addToPiece(child)
+			newPiece()
+		default:
+			addToPiece(child)
+		}
+	}
+	if len(pieces) == 1 {
+		return
+	}
+
+	// the last piece stays in the paragraph, the others are moved to
+	// paragraphs before it and the blank ones are dropped
+	for _, piece := range pieces[:len(pieces)-1] {
+		isBlank := true
+		for _, node := range piece {
+			isBlank = isBlank && isBlankText(node)
+		}
+		if isBlank {
+			for _, node := range piece {
+				node.Unlink()
+			}
+			continue
+		}
+
+		pieceParagraph := blackfriday.NewNode(blackfriday.Paragraph)
+		for _, node := range piece {
+			pieceParagraph.AppendChild(node)
+		}
+		paragraph.InsertBefore(pieceParagraph)
+	}
+}
+
+// firstNonBlankChild returns the first child of the node which is not
+// whitespace, or nil.
+func firstNonBlankChild(node *blackfriday.Node) *blackfriday.Node {
+	for child := node.FirstChild; child != nil; child = child.Next {
+		if !isBlankText(child) {
+			return child
+		}
+	}
+	return nil
+}
+
+func isDetailsStart(node *blackfriday.Node) bool {
+	if node.Type != blackfriday.Paragraph {
+		return false
+	}
+	child := firstNonBlankChild(node)
+	return child != nil && isHTMLTag(child, detailsStartRe)
+}
+
+func isDetailsEnd(node *blackfriday.Node) bool {
+	if node.Type != blackfriday.Paragraph {
+		return false
+	}
+	child := firstNonBlankChild(node)
+	return child != nil && isHTMLTag(child, detailsEndRe)
+}
+
+// findDetailsEnd returns the paragraph closing the <details> block which
+// the node starts, or nil if the block is not closed by the stop node.
+func findDetailsEnd(
+	node *blackfriday.Node, stopNode *blackfriday.Node,
+) *blackfriday.Node {
+	depth := 0
+	for ; node != stopNode.Next; node = node.Next {
+		if isDetailsStart(node) {
+			depth++
+		} else if isDetailsEnd(node) {
+			depth--
+			if depth == 0 {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+// parseSummary returns the text of the summary of a <details> block, which
+// is the title of the expand.
+func parseSummary(node *blackfriday.Node) string {
+	title := ""
+	inSummary := false
+	for child := node.FirstChild; child != nil; child = child.Next {
+		switch {
+		case isHTMLTag(child, summaryStartRe):
+			inSummary = true
+		case isHTMLTag(child, summaryEndRe):
+			inSummary = false
+		case inSummary:
+			title += parseText(child)
+		}
+	}
+	return strings.TrimSpace(title)
+}
+
+func tableAlignment(align blackfriday.CellAlignFlags) domain.TableAlignment {
+	switch align {
+	case blackfriday.TableAlignmentCenter:
+		return domain.TableAlignmentCenter
+	case blackfriday.TableAlignmentRight:
+		return domain.TableAlignmentRight
+	default:
+		return domain.TableAlignmentLeft
+	}
+}
+
+// parseTable reads the rows of a table. A header row of empty cells is not
+// kept: it only makes the rows below it a table.
+func parseTable(node *blackfriday.Node, domainDoc *domain.Document) {
+	head, body := node.FirstChild, node.LastChild
+	headRow := head.FirstChild
+
+	alignments := []domain.TableAlignment{}
+	for cell := headRow.FirstChild; cell != nil; cell = cell.Next {
+		alignment := tableAlignment(cell.TableCellData.Align)
+		alignments = append(alignments, alignment)
+	}
+	table := domainDoc.AddTable(alignments...)
+
+	addRow := func(row *blackfriday.Node, tableRow *domain.TableRow) {
+		for cell := row.FirstChild; cell != nil; cell = cell.Next {
+			parseTextContainer(cell, tableRow.AddCell())
+		}
+	}
+	if !isNodeEmpty(headRow) {
+		addRow(headRow, table.AddHeaderRow())
+	}
+	for row := body.FirstChild; row != nil; row = row.Next {
+		addRow(row, table.AddRow())
+	}
+}
+
+func parseBlocks(
+	startNode *blackfriday.Node,
+	stopNode *blackfriday.Node,
+	domainDoc *domain.Document,
+) error {
+	for node := startNode; node != stopNode.Next; node = node.Next {
+		switch node.Type {
+		case blackfriday.Paragraph:
+			if isDetailsEnd(node) {
+				// closing tags without an opening tag are dropped
+				continue
+			}
+			if !isDetailsStart(node) {
+				parseParagraph(node, domainDoc)
+				continue
+			}
+
+			// unclosed <details> blocks run to the end
+			endNode := findDetailsEnd(node, stopNode)
+			contentStopNode := stopNode
+			if endNode != nil {
+				contentStopNode = endNode.Prev
+			}
+			expand := domainDoc.AddExpand(parseSummary(node))
+			if node != contentStopNode {
+				err := parseBlocks(
+					node.Next, contentStopNode, &expand.Document,
+				)
+				if err != nil {
+					return err
+				}
+			}
+			if endNode == nil {
+				return nil
+			}
+			node = endNode
+
+		case blackfriday.List:
+			var list *domain.ListData
+			if isTaskList(node) {
+				list = domainDoc.AddTaskList()
+			} else if node.ListData.ListFlags&blackfriday.ListTypeOrdered ==
+				blackfriday.ListTypeOrdered {
+				list = domainDoc.AddOrderedList()
+			} else {
+				list = domainDoc.AddUnorderedList()
+			}
+			if err := parseList(node, list); err != nil {
+				return err
+			}
+
+		case blackfriday.CodeBlock:
+			domainDoc.AddCodeBlock(
+				string(node.CodeBlockData.Info), string(node.Literal),
+			)
+
+		case blackfriday.Heading:
+			text := parseText(node)
+			domainDoc.AddHeading(headingLevel(node.HeadingData.Level), text)
+
+		case blackfriday.BlockQuote:
+			var quoteDoc *domain.Document
+			if panelType, ok := parseCallout(node); ok {
+				quoteDoc = &domainDoc.AddPanel(panelType).Document
+			} else {
+				quoteDoc = &domainDoc.AddBlockQuote().Document
+			}
+			if node.FirstChild == nil {
+				continue
+			}
+			err := parseBlocks(node.FirstChild, node.LastChild, quoteDoc)
+			if err != nil {
+				return err
+			}
+
+		case blackfriday.Table:
+			parseTable(node, domainDoc)
+
+		case blackfriday.HorizontalRule:
+			domainDoc.AddRule()
+
+		case blackfriday.HTMLBlock:
+			// comments separate blocks which would otherwise be merged
+			if !isHTMLComment(node) {
+				return fmt.Errorf("Unknown node type: %s", node.Type)
+			}
+
+		default:
+			return fmt.Errorf("Unknown node type: %s", node.Type)
+		}
+	}
+	return nil
+}
+
+func (s *section) parseDescription(incLastNode bool) (*domain.Document, error) {
+	if s.firstNode == s.lastNode {
+		// no description
+		return nil, nil
+	}
+
+	startNode := s.firstNode.Next
+	stopNode := s.lastNode.Prev
+	if incLastNode {
+		stopNode = s.lastNode
+	}
+	domainDoc := &domain.Document{}
+	if err := parseBlocks(startNode, stopNode, domainDoc); err != nil {
+		return nil, err
+	}
+	if len(domainDoc.Nodes) == 0 {
+		// only the header and the footer
+		return nil, nil
+	}
+	return domainDoc, nil
+}
@@ -0,0 +1,1831 @@
+package markdown_test
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/russross/blackfriday/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/markdown"
+)
+
+/******************************************************************************
+ * General Parsing
+ *****************************************************************************/
+
+func TestMarkdownParserEmpty(t *testing.T) {
+	input := ""
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 0)
+}
+
+func TestMarkdownParserSingleBug(t *testing.T) {
+	input := `[Bug] Bug title
+
+Test para.
+
+Test list:
+
+- A
+- B
+
+Epic: 123
+Labels: label-1, label-2
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+	require.Equal(t, "Bug", issues[0].Type.String())
+	require.Equal(t, "Bug title", issues[0].Title)
+	require.Equal(t, "123", issues[0].Epic.ID)
+	require.Equal(
+		t, []domain.Label{
+			{Label: "label-1"},
+			{Label: "label-2"},
+		}, issues[0].Labels,
+	)
+}
+
+func TestMarkdownParserMultipleIssues(t *testing.T) {
+	input := `[Bug] Bug title
+
+Test para.
+
+Test list:
+
+- A
+- B
+
+Epic: 123
+Labels: label-1, label-2
+
+---
+
+A story
+
+Hello world.
+
+Epic: 99
+Labels: label-3
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 2)
+	require.Equal(t, "Bug", issues[0].Type.String())
+	require.Equal(t, "Bug title", issues[0].Title)
+	require.Equal(t, "123", issues[0].Epic.ID)
+	require.Equal(
+		t, []domain.Label{
+			{Label: "label-1"},
+			{Label: "label-2"},
+		}, issues[0].Labels,
+	)
+
+	require.Equal(t, "User Story", issues[1].Type.String())
+	require.Equal(t, "A story", issues[1].Title)
+	require.Equal(t, "99", issues[1].Epic.ID)
+	require.Equal(
+		t, []domain.Label{
+			{Label: "label-3"},
+		}, issues[1].Labels,
+	)
+}
+
+/******************************************************************************
+ * Parsing story type
+ *****************************************************************************/
+
+func TestMarkdownParserChore(t *testing.T) {
+	// Chore
+	input := "[Chore] Chore title"
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(t, "Chore", issues[0].Type.String())
+	require.Equal(t, "Chore title", issues[0].Title)
+
+	// Task
+	input = "[Task] Task title"
+	issues, err = markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(t, "Chore", issues[0].Type.String())
+	require.Equal(t, "Task title", issues[0].Title)
+}
+
+func TestMarkdownParserUserStory(t *testing.T) {
+	// No tag
+	input := "Title"
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(t, "User Story", issues[0].Type.String())
+	require.Equal(t, "Title", issues[0].Title)
+
+	// Story
+	input = "[Story] Story title"
+	issues, err = markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(t, "User Story", issues[0].Type.String())
+	require.Equal(t, "Story title", issues[0].Title)
+
+	// Issue
+	input = "[Issue] Issue title"
+	issues, err = markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(t, "User Story", issues[0].Type.String())
+	require.Equal(t, "Issue title", issues[0].Title)
+}
+
+/******************************************************************************
+ * Epic
+ *****************************************************************************/
+
+func TestMarkdownParserEpic(t *testing.T) {
+	// Using E
+	input := `Title
+
+E: hello-world`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(t, "hello-world", issues[0].Epic.ID)
+
+	// Using Epic
+	input = `Title
+
+Epic: hello-world`
+	issues, err = markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(t, "hello-world", issues[0].Epic.ID)
+
+	// Epic ID w/ numbers
+	input = `Title
+
+Epic: 1234`
+	issues, err = markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(t, "1234", issues[0].Epic.ID)
+
+	// Epic ID with spaces
+	input = `Title
+
+Epic:   hello-world      `
+	issues, err = markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(t, "hello-world", issues[0].Epic.ID)
+
+	// No epic
+	input = "Title"
+	issues, err = markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Nil(t, issues[0].Epic)
+}
+
+/******************************************************************************
+ * Labels
+ *****************************************************************************/
+
+func TestMarkdownParserLabels(t *testing.T) {
+	// Using L
+	input := `Title
+
+L: label-1, label-2`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(
+		t, []domain.Label{
+			{Label: "label-1"},
+			{Label: "label-2"},
+		}, issues[0].Labels,
+	)
+
+	// Using Labels
+	input = `Title
+
+Labels: label-1, label-2`
+	issues, err = markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(
+		t, []domain.Label{
+			{Label: "label-1"},
+			{Label: "label-2"},
+		}, issues[0].Labels,
+	)
+
+	// Labels without spaces
+	input = `Title
+
+Labels: label-1,label-2`
+	issues, err = markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(
+		t, []domain.Label{
+			{Label: "label-1"},
+			{Label: "label-2"},
+		}, issues[0].Labels,
+	)
+
+	// Labels with many spaces
+	input = `Title
+
+Labels:      label-1,     label-2    `
+	issues, err = markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(
+		t, []domain.Label{
+			{Label: "label-1"},
+			{Label: "label-2"},
+		}, issues[0].Labels,
+	)
+
+	// No labels
+	input = "Title"
+	issues, err = markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Empty(t, issues[0].Labels)
+}
+
+func TestMarkdownParserAttachments(t *testing.T) {
+	input := `[Bug] Title
+
+It crashed.
+
+E: EPIC-1
+Attach: ./logs/crash.log,  ./trace.har ,`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(t, &domain.Epic{ID: "EPIC-1"}, issues[0].Epic)
+	require.Equal(
+		t, []domain.Attachment{
+			{Path: "./logs/crash.log"},
+			{Path: "./trace.har"},
+		}, issues[0].Attachments,
+	)
+	expected := &domain.Document{}
+	expected.AddParagraph().AddText("It crashed.", domain.TextMode{})
+	require.Equal(t, expected, issues[0].Description)
+
+	// Attachments only
+	input = `Title
+
+Attach: crash.log`
+	issues, err = markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(
+		t, []domain.Attachment{{Path: "crash.log"}}, issues[0].Attachments,
+	)
+	require.Nil(t, issues[0].Description)
+}
+
+/******************************************************************************
+ * Horizontal rules in the beginning and/or the end of the file
+ *****************************************************************************/
+
+func TestMarkdownParserSingleBugStartingWHR(t *testing.T) {
+	input := `---
+
+[Bug] Bug title
+
+Test para.
+
+Test list:
+
+- A
+- B
+
+Epic: 123
+Labels: label-1, label-2
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+}
+
+func TestMarkdownParserSingleBugEndingWHR(t *testing.T) {
+	input := `[Bug] Bug title
+
+Test para.
+
+Test list:
+
+- A
+- B
+
+Epic: 123
+Labels: label-1, label-2
+
+---
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+}
+
+func TestMarkdownParserSingleBugSurroundedByHR(t *testing.T) {
+	input := `---
+
+[Bug] Bug title
+
+Test para.
+
+Test list:
+
+- A
+- B
+
+Epic: 123
+Labels: label-1, label-2
+
+---
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+}
+
+func TestMarkdownParserMultipleIssuesWithMultipleHR(t *testing.T) {
+	input := `[Bug] Bug title
+
+Test para.
+
+Test list:
+
+- A
+- B
+
+Epic: 123
+Labels: label-1, label-2
+
+---
+
+---
+---
+
+A story
+
+Hello world.
+
+Epic: 99
+Labels: label-3
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 2)
+}
+
+func TestMarkdownParserSeparator(t *testing.T) {
+	input := `<!-- issue -->
+
+[Bug] Bug title
+
+Before
+
+---
+
+After
+
+` + "```" + `
+<!-- issue -->
+` + "```" + `
+
+Epic: 123
+
+<!-- issue -->
+<!-- issue -->
+
+[Chore] A chore
+`
+	issues, err := markdown.ParseImportFileWithOptions(
+		strings.NewReader(input),
+		markdown.ParserOptions{Separator: "<!-- issue -->"},
+	)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	expected.AddParagraph().AddText("Before", domain.TextMode{})
+	expected.AddRule()
+	expected.AddParagraph().AddText("After", domain.TextMode{})
+	expected.AddCodeBlock("", "<!-- issue -->\n")
+
+	require.Len(t, issues, 2)
+	require.Equal(t, "Bug title", issues[0].Title)
+	require.Equal(t, expected, issues[0].Description)
+	require.Equal(t, &domain.Epic{ID: "123"}, issues[0].Epic)
+	require.Equal(t, "A chore", issues[1].Title)
+	require.Equal(t, domain.IssueTypeChore, issues[1].Type)
+}
+
+func TestMarkdownParserHeadingSections(t *testing.T) {
+	input := `# Planning
+
+Notes which are not part of any issue.
+
+## [Bug] Bug title
+
+### Steps
+
+Before
+
+---
+
+After
+
+# PROJ-12: Onboarding
+
+## A story
+
+Labels: label-1
+
+## [Chore] A chore
+
+Epic: PROJ-99
+`
+	issues, err := markdown.ParseImportFileWithOptions(
+		strings.NewReader(input),
+		markdown.ParserOptions{Sections: markdown.SectionModeHeadings},
+	)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	expected.AddHeading(domain.HeadingLevel3, "Steps")
+	expected.AddParagraph().AddText("Before", domain.TextMode{})
+	expected.AddRule()
+	expected.AddParagraph().AddText("After", domain.TextMode{})
+
+	require.Len(t, issues, 3)
+	require.Equal(t, "Bug title", issues[0].Title)
+	require.Equal(t, domain.IssueTypeBug, issues[0].Type)
+	require.Nil(t, issues[0].Epic)
+	require.Equal(t, expected, issues[0].Description)
+	require.Equal(t, "A story", issues[1].Title)
+	require.Equal(t, &domain.Epic{ID: "PROJ-12"}, issues[1].Epic)
+	require.Nil(t, issues[1].Description)
+	require.Equal(t, []domain.Label{{Label: "label-1"}}, issues[1].Labels)
+	require.Equal(t, "A chore", issues[2].Title)
+	require.Equal(t, &domain.Epic{ID: "PROJ-99"}, issues[2].Epic)
+}
+
+func TestMarkdownParserHeadingSectionsLevel(t *testing.T) {
+	input := "## PROJ-12\n\n### First\n\n#### Details\n\n### Second\n"
+	issues, err := markdown.ParseImportFileWithOptions(
+		strings.NewReader(input),
+		markdown.ParserOptions{
+			Sections:     markdown.SectionModeHeadings,
+			HeadingLevel: 3,
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	require.Equal(t, "First", issues[0].Title)
+	require.Equal(t, &domain.Epic{ID: "PROJ-12"}, issues[0].Epic)
+	require.Len(t, issues[0].Description.Nodes, 1)
+	require.Equal(t, "Second", issues[1].Title)
+
+	_, err = markdown.ParseImportFileWithOptions(
+		strings.NewReader(input),
+		markdown.ParserOptions{
+			Sections:     markdown.SectionModeHeadings,
+			HeadingLevel: 7,
+		},
+	)
+	require.Error(t, err)
+
+	_, err = markdown.ParseSectionMode("chapters")
+	require.Error(t, err)
+}
+
+func TestMarkdownParserProlific(t *testing.T) {
+	input := `As a user I can sign up
+Users sign up with *email* and password.
+L: onboarding,signup
+---
+[CHORE] Update Go
+---
+[BUG] Sign up fails
+L: bugs
+---
+[Feature] As a user I can log in
+---
+[RELEASE] Alpha
+`
+	issues, err := markdown.ParseImportFileWithOptions(
+		strings.NewReader(input), markdown.ParserOptions{Prolific: true},
+	)
+	require.NoError(t, err)
+
+	description := &domain.Document{}
+	p := description.AddParagraph()
+	p.AddText("Users sign up with ", domain.TextMode{})
+	p.AddText("email", domain.TextMode{Italics: true})
+	p.AddText(" and password.", domain.TextMode{})
+
+	require.Equal(t, []*domain.Issue{
+		{
+			Type:        domain.IssueTypeStory,
+			Title:       "As a user I can sign up",
+			Description: description,
+			Labels: []domain.Label{
+				{Label: "onboarding"},
+				{Label: "signup"},
+			},
+		},
+		{
+			Type:  domain.IssueTypeChore,
+			Title: "Update Go",
+		},
+		{
+			Type:   domain.IssueTypeBug,
+			Title:  "Sign up fails",
+			Labels: []domain.Label{{Label: "bugs"}},
+		},
+		{
+			Type:  domain.IssueTypeStory,
+			Title: "As a user I can log in",
+		},
+		{
+			Type:  domain.IssueTypeRelease,
+			Title: "Alpha",
+		},
+	}, issues)
+}
+
+func TestMarkdownParserProlificLayout(t *testing.T) {
+	input := "[FEATURE] A\ndesc\nL: a, b\n---\n[BUG] B\n"
+	issues, err := markdown.ParseImportFile(strings.NewReader(input))
+	require.NoError(t, err)
+
+	description := &domain.Document{}
+	description.AddParagraph().AddText("desc", domain.TextMode{})
+	require.Equal(t, []*domain.Issue{
+		{
+			Type:        domain.IssueTypeStory,
+			Title:       "A",
+			Description: description,
+			Labels:      []domain.Label{{Label: "a"}, {Label: "b"}},
+		},
+		{
+			Type:  domain.IssueTypeBug,
+			Title: "B",
+		},
+	}, issues)
+}
+
+func TestMarkdownParserSetextHeading(t *testing.T) {
+	input := `[Bug] Bug title
+
+Steps to reproduce
+---
+
+Run it
+
+---
+
+[Chore] A chore
+`
+	issues, err := markdown.ParseImportFile(strings.NewReader(input))
+	require.NoError(t, err)
+
+	description := &domain.Document{}
+	description.AddHeading(domain.HeadingLevel2, "Steps to reproduce")
+	description.AddParagraph().AddText("Run it", domain.TextMode{})
+
+	require.Len(t, issues, 2)
+	require.Equal(t, "Bug title", issues[0].Title)
+	require.Equal(t, description, issues[0].Description)
+	require.Equal(t, "A chore", issues[1].Title)
+}
+
+func TestMarkdownParserTypeAliases(t *testing.T) {
+	input := `[Spike] Look into caching
+
+---
+
+[defect] Crash on start
+`
+	issues, err := markdown.ParseImportFileWithOptions(
+		strings.NewReader(input), markdown.ParserOptions{
+			TypeAliases: map[string]domain.IssueType{
+				"spike":  domain.IssueTypeChore,
+				"Defect": domain.IssueTypeBug,
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 2)
+	require.Equal(t, domain.IssueTypeChore, issues[0].Type)
+	require.Equal(t, domain.IssueTypeBug, issues[1].Type)
+
+	_, err = markdown.ParseImportFile(strings.NewReader(input))
+	require.EqualError(
+		t, err,
+		"Failed parsing issue 1 in markdown file: Unknown issue type Spike",
+	)
+}
+
+func TestMarkdownParserIssueErrorLine(t *testing.T) {
+	cases := []struct {
+		input   string
+		options markdown.ParserOptions
+		line    int
+	}{
+		{
+			input: "Title\n\nText\n\n---\n\n[Spike] Look\n",
+			line:  7,
+		},
+		{
+			input: "[Bug] A\n===\n\n[Spike] Look\n",
+			options: markdown.ParserOptions{
+				Separator: "===",
+			},
+			line: 4,
+		},
+		{
+			input: "# PROJ-1\n\n## A\n\nText\n\n## [Spike] Look\n",
+			options: markdown.ParserOptions{
+				Sections: markdown.SectionModeHeadings,
+			},
+			line: 7,
+		},
+		{
+			input: "Title\n\n```\n---\n```\n\n***\n\n\n[Spike] Look\n",
+			line:  10,
+		},
+		{
+			input: "[Bug] A\nText\n---\n[Spike] Look\n",
+			line:  4,
+		},
+		{
+			input: "PROJ-1\n===\n\nA\n---\n\n[Spike] Look\n---\n",
+			options: markdown.ParserOptions{
+				Sections: markdown.SectionModeHeadings,
+			},
+			line: 7,
+		},
+	}
+	for _, c := range cases {
+		_, err := markdown.ParseImportFileWithOptions(
+			strings.NewReader(c.input), c.options,
+		)
+		require.Error(t, err)
+		issueErr, ok := err.(*markdown.IssueError)
+		require.True(t, ok)
+		require.Equal(t, 2, issueErr.Issue)
+		require.Equal(t, c.line, issueErr.Line)
+	}
+}
+
+func TestMarkdownParserExtensions(t *testing.T) {
+	input := `Title
+
+~~Struck~~ text.
+`
+	issues, err := markdown.ParseImportFile(strings.NewReader(input))
+	require.NoError(t, err)
+	text := issues[0].Description.Nodes[0].ParagraphData.Content.Elements[0]
+	require.True(t, text.Mode.Strikethrough)
+
+	issues, err = markdown.ParseImportFileWithOptions(
+		strings.NewReader(input), markdown.ParserOptions{
+			Extensions: blackfriday.FencedCode,
+		},
+	)
+	require.NoError(t, err)
+	text = issues[0].Description.Nodes[0].ParagraphData.Content.Elements[0]
+	require.False(t, text.Mode.Strikethrough)
+}
+
+/******************************************************************************
+ * Parse description
+ *****************************************************************************/
+
+func TestMarkdownParserDescription(t *testing.T) {
+	input := `[Bug] Bug title
+
+Test para.
+
+Test list:
+
+- A
+- B
+
+Epic: 123
+Labels: label-1, label-2
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+	require.Equal(
+		t, &domain.Document{
+			Nodes: []domain.DocumentNode{
+				{
+					Type: domain.DocumentNodeTypeParagraph,
+					ParagraphData: &domain.ParagraphData{
+						Content: domain.TextContainer{
+							Elements: []domain.TextElement{
+								{Text: "Test para."},
+							},
+						},
+					},
+				},
+				{
+					Type: domain.DocumentNodeTypeParagraph,
+					ParagraphData: &domain.ParagraphData{
+						Content: domain.TextContainer{
+							Elements: []domain.TextElement{
+								{Text: "Test list:"},
+							},
+						},
+					},
+				},
+				{
+					Type: domain.DocumentNodeTypeList,
+					ListData: &domain.ListData{
+						IsOrdered: false,
+						Items: []domain.ListItem{
+							{
+								TextContainer: domain.TextContainer{
+									Elements: []domain.TextElement{
+										{Text: "A"},
+									},
+								},
+							},
+							{
+								TextContainer: domain.TextContainer{
+									Elements: []domain.TextElement{
+										{Text: "B"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}, issues[0].Description,
+	)
+}
+
+func TestMarkdownParserDescriptionWithMarks(t *testing.T) {
+	input := `[Bug] Bug title
+
+Test para **bold** and _italics_ and ~~strikethrough~~.
+Sometimes, ~~**combinations**~~ of both. And some [links](https://google.com).
+And **[links with marks](https://google.com)**.
+
+- **Bold** list item
+
+Epic: 123
+Labels: label-1, label-2
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+	require.Equal(
+		t, &domain.Document{
+			Nodes: []domain.DocumentNode{
+				{
+					Type: domain.DocumentNodeTypeParagraph,
+					ParagraphData: &domain.ParagraphData{
+						Content: domain.TextContainer{
+							Elements: []domain.TextElement{
+								{Text: "Test para "},
+								{
+									Text: "bold",
+									Mode: domain.TextMode{Bold: true},
+								},
+								{Text: " and "},
+								{
+									Text: "italics",
+									Mode: domain.TextMode{Italics: true},
+								},
+								{Text: " and "},
+								{
+									Text: "strikethrough",
+									Mode: domain.TextMode{Strikethrough: true},
+								},
+								{Text: ".\nSometimes, "},
+								{
+									Text: "combinations",
+									Mode: domain.TextMode{
+										Strikethrough: true,
+										Bold:          true,
+									},
+								},
+								{Text: " of both. And some "},
+								{
+									Text:    "links",
+									LinkURL: "https://google.com",
+								},
+								{Text: ".\nAnd "},
+								{
+									Text:    "links with marks",
+									Mode:    domain.TextMode{Bold: true},
+									LinkURL: "https://google.com",
+								},
+								{Text: "."},
+							},
+						},
+					},
+				},
+				{
+					Type: domain.DocumentNodeTypeList,
+					ListData: &domain.ListData{
+						IsOrdered: false,
+						Items: []domain.ListItem{
+							{
+								TextContainer: domain.TextContainer{
+									Elements: []domain.TextElement{
+										{
+											Text: "Bold",
+											Mode: domain.TextMode{Bold: true},
+										},
+										{Text: " list item"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}, issues[0].Description,
+	)
+}
+
+func TestMarkdownParserDescriptionWithHTMLMarks(t *testing.T) {
+	input := `[Bug] Bug title
+
+<u>Underlined **bold**</u> H<sub>2</sub>O x<sup>2</sup>\
+<span style="color: #FF5630">red <span>still red</span></span> <b>plain</b>
+two spaces  
+break
+
+Epic: 123
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	p := expected.AddParagraph()
+	p.AddText("Underlined ", domain.TextMode{Underline: true})
+	p.AddText("bold", domain.TextMode{Underline: true, Bold: true})
+	p.AddText(" H", domain.TextMode{})
+	p.AddText("2", domain.TextMode{Subscript: true})
+	p.AddText("O x", domain.TextMode{})
+	p.AddText("2", domain.TextMode{Superscript: true})
+	p.AddHardBreak()
+	p.AddText("red still red", domain.TextMode{Color: "#ff5630"})
+	p.AddText(" plain\ntwo spaces", domain.TextMode{})
+	p.AddHardBreak()
+	p.AddText("break", domain.TextMode{})
+
+	require.Len(t, issues, 1)
+	require.Equal(t, expected, issues[0].Description)
+}
+
+func TestMarkdownParserDescriptionWithInlineNodes(t *testing.T) {
+	input := `[Story] Story title
+
+Ask @[Alice Smith] and @bob@example.com :warning: by {date:2026-11-01}.
+Not at 12:30:45, in ` + "`:code:`" + `, on {date:2026-13-01}, \:escaped:
+or in [@[Link]](https://e.com).
+
+Epic: 123
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	p := expected.AddParagraph()
+	p.AddText("Ask ", domain.TextMode{})
+	p.AddMention("Alice Smith", "")
+	p.AddText(" and ", domain.TextMode{})
+	p.AddMention("bob@example.com", "")
+	p.AddText(" ", domain.TextMode{})
+	p.AddEmoji("warning")
+	p.AddText(" by ", domain.TextMode{})
+	p.AddDate("2026-11-01")
+	p.AddText(".\nNot at 12:30:45, in ", domain.TextMode{})
+	p.AddText(":code:", domain.TextMode{Code: true})
+	p.AddText(
+		", on {date:2026-13-01}, :escaped:\nor in ", domain.TextMode{},
+	)
+	p.AddLink("@[Link]", "https://e.com", domain.TextMode{})
+	p.AddText(".", domain.TextMode{})
+
+	require.Len(t, issues, 1)
+	require.Equal(t, expected, issues[0].Description)
+}
+
+func TestMarkdownParserDescriptionWithoutEmojis(t *testing.T) {
+	texts := []string{
+		"use std::vector::push and 12:30:45",
+		"at 12:30:45:",
+		"see a::b:: and :c::d",
+		"in foo:bar:baz or :bar:baz or :bar:_",
+	}
+	for _, text := range texts {
+		issues, err := markdown.ParseImportFile(
+			strings.NewReader("[Story] Title\n\n" + text + "\n"),
+		)
+		require.NoError(t, err)
+
+		expected := &domain.Document{}
+		expected.AddParagraph().AddText(text, domain.TextMode{})
+		require.Equal(t, expected, issues[0].Description, text)
+	}
+
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader("[Story] Title\n\nlog in to http://a:b@host\n"),
+	)
+	require.NoError(t, err)
+	expected := &domain.Document{}
+	p := expected.AddParagraph()
+	p.AddText("log in to ", domain.TextMode{})
+	p.AddLink("http://a:b@host", "http://a:b@host", domain.TextMode{})
+	require.Equal(t, expected, issues[0].Description)
+
+	issues, err = markdown.ParseImportFile(
+		strings.NewReader("[Story] Title\n\nShip it :+1::tada:!\n"),
+	)
+	require.NoError(t, err)
+	expected = &domain.Document{}
+	p = expected.AddParagraph()
+	p.AddText("Ship it ", domain.TextMode{})
+	p.AddEmoji("+1")
+	p.AddEmoji("tada")
+	p.AddText("!", domain.TextMode{})
+	require.Equal(t, expected, issues[0].Description)
+}
+
+func TestMarkdownParserDescriptionWithAutolinks(t *testing.T) {
+	input := `[Story] Story title
+
+See https://example.com/a, <https://example.com/b>, [c](https://example.com/c)
+and <bob@example.com> but not https\://example.com/d.
+
+Epic: 123
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	p := expected.AddParagraph()
+	p.AddText("See ", domain.TextMode{})
+	p.AddLink(
+		"https://example.com/a", "https://example.com/a", domain.TextMode{},
+	)
+	p.AddText(", ", domain.TextMode{})
+	p.AddLink(
+		"https://example.com/b", "https://example.com/b", domain.TextMode{},
+	)
+	p.AddText(", ", domain.TextMode{})
+	p.AddLink("c", "https://example.com/c", domain.TextMode{})
+	p.AddText("\nand ", domain.TextMode{})
+	p.AddLink("bob@example.com", "mailto:bob@example.com", domain.TextMode{})
+	p.AddText(" but not https://example.com/d.", domain.TextMode{})
+
+	require.Len(t, issues, 1)
+	require.Equal(t, expected, issues[0].Description)
+}
+
+func TestMarkdownParserDescriptionWithDetails(t *testing.T) {
+	input := "[Bug] Bug title\n\n" +
+		"Crashes on start.\n" +
+		"<details>\n<summary>Stack trace</summary>\n\n" +
+		"```\npanic: oops\n```\n\n" +
+		"<details><summary>Logs</summary>\nNested *logs*\n</details>\n\n" +
+		"</details>\n\n" +
+		"- Item\n\n" +
+		"    <details>\n    No summary\n    </details>\n\n" +
+		"Epic: 123\n"
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	expected.AddParagraph().AddText("Crashes on start.", domain.TextMode{})
+	expand := expected.AddExpand("Stack trace")
+	expand.AddCodeBlock("", "panic: oops\n")
+	p := expand.AddExpand("Logs").AddParagraph()
+	p.AddText("Nested ", domain.TextMode{})
+	p.AddText("logs", domain.TextMode{Italics: true})
+	item := expected.AddUnorderedList().AddItem()
+	item.AddText("Item", domain.TextMode{})
+	item.AddExpand("").AddParagraph().AddText(
+		"No summary", domain.TextMode{},
+	)
+
+	require.Len(t, issues, 1)
+	require.Equal(t, expected, issues[0].Description)
+}
+
+func TestMarkdownParserDescriptionWithDetailsSummaryParagraph(t *testing.T) {
+	input := "[Bug] Bug title\n\n" +
+		"<details>\n\n<summary>Stack trace</summary>\npanic: oops\n\n" +
+		"More\n\n</details>\n\n" +
+		"Epic: 123\n"
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	expand := expected.AddExpand("Stack trace")
+	expand.AddParagraph().AddText("panic: oops", domain.TextMode{})
+	expand.AddParagraph().AddText("More", domain.TextMode{})
+
+	require.Len(t, issues, 1)
+	require.Equal(t, expected, issues[0].Description)
+}
+
+func TestMarkdownParserDescriptionWithCode(t *testing.T) {
+	input := "[Bug] Bug title\n\nTest para `with code`.\n\nEpic: 123"
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+	require.Equal(
+		t, &domain.Document{
+			Nodes: []domain.DocumentNode{
+				{
+					Type: domain.DocumentNodeTypeParagraph,
+					ParagraphData: &domain.ParagraphData{
+						Content: domain.TextContainer{
+							Elements: []domain.TextElement{
+								{Text: "Test para "},
+								{
+									Text: "with code",
+									Mode: domain.TextMode{Code: true},
+								},
+								{Text: "."},
+							},
+						},
+					},
+				},
+			},
+		}, issues[0].Description,
+	)
+}
+
+func TestMarkdownParserDescriptionWithCodeBlock(t *testing.T) {
+	input := "[Bug] Bug title\n\nTest para with code block:\n\n" +
+		"```python\nx = 12\n```\n\nEpic: 123"
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+	require.Equal(
+		t, &domain.Document{
+			Nodes: []domain.DocumentNode{
+				{
+					Type: domain.DocumentNodeTypeParagraph,
+					ParagraphData: &domain.ParagraphData{
+						Content: domain.TextContainer{
+							Elements: []domain.TextElement{
+								{Text: "Test para with code block:"},
+							},
+						},
+					},
+				},
+				{
+					Type: domain.DocumentNodeTypeCodeBlock,
+					CodeBlockData: &domain.CodeBlockData{
+						Code:     "x = 12\n",
+						Language: "python",
+					},
+				},
+			},
+		}, issues[0].Description,
+	)
+}
+
+func TestMarkdownParserDescriptionHeadings(t *testing.T) {
+	input := `[Bug] Bug title
+
+# Title
+
+Hello world.
+
+## Subtitle
+
+Test test.
+
+Epic: 123
+Labels: label-1, label-2
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+	require.Equal(
+		t, &domain.Document{
+			Nodes: []domain.DocumentNode{
+				{
+					Type: domain.DocumentNodeTypeHeading,
+					HeadingData: &domain.HeadingData{
+						Level: domain.HeadingLevel1,
+						Text:  "Title",
+					},
+				},
+				{
+					Type: domain.DocumentNodeTypeParagraph,
+					ParagraphData: &domain.ParagraphData{
+						Content: domain.TextContainer{
+							Elements: []domain.TextElement{
+								{Text: "Hello world."},
+							},
+						},
+					},
+				},
+				{
+					Type: domain.DocumentNodeTypeHeading,
+					HeadingData: &domain.HeadingData{
+						Level: domain.HeadingLevel2,
+						Text:  "Subtitle",
+					},
+				},
+				{
+					Type: domain.DocumentNodeTypeParagraph,
+					ParagraphData: &domain.ParagraphData{
+						Content: domain.TextContainer{
+							Elements: []domain.TextElement{
+								{Text: "Test test."},
+							},
+						},
+					},
+				},
+			},
+		}, issues[0].Description,
+	)
+}
+
+func TestMarkdownParserDescriptionOrderedLists(t *testing.T) {
+	input := `[Bug] Bug title
+
+An ordered list:
+
+1. List item 1 
+1. List item 2 
+1. List item 3
+
+Epic: 123
+Labels: label-1, label-2
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+	require.Equal(
+		t, &domain.Document{
+			Nodes: []domain.DocumentNode{
+				{
+					Type: domain.DocumentNodeTypeParagraph,
+					ParagraphData: &domain.ParagraphData{
+						Content: domain.TextContainer{
+							Elements: []domain.TextElement{
+								{Text: "An ordered list:"},
+							},
+						},
+					},
+				},
+				{
+					Type: domain.DocumentNodeTypeList,
+					ListData: &domain.ListData{
+						IsOrdered: true,
+						Items: []domain.ListItem{
+							{
+								TextContainer: domain.TextContainer{
+									Elements: []domain.TextElement{
+										{Text: "List item 1"},
+									},
+								},
+							},
+							{
+								TextContainer: domain.TextContainer{
+									Elements: []domain.TextElement{
+										{Text: "List item 2"},
+									},
+								},
+							},
+							{
+								TextContainer: domain.TextContainer{
+									Elements: []domain.TextElement{
+										{Text: "List item 3"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}, issues[0].Description,
+	)
+}
+
+func TestMarkdownParserDescriptionNestedLists(t *testing.T) {
+	input := `[Bug] Bug title
+
+- Item 1
+    - Item 1.1
+        1. Item 1.1.1
+    - Item 1.2
+- Item 2
+
+    More about item 2.
+
+    ` + "```" + `
+    x = 12
+    ` + "```" + `
+
+Epic: 123
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	list := expected.AddUnorderedList()
+	item1 := list.AddItem()
+	item1.AddText("Item 1", domain.TextMode{})
+	list1 := item1.AddUnorderedList()
+	item11 := list1.AddItem()
+	item11.AddText("Item 1.1", domain.TextMode{})
+	item11.AddOrderedList().AddItem().AddText("Item 1.1.1", domain.TextMode{})
+	list1.AddItem().AddText("Item 1.2", domain.TextMode{})
+	item2 := list.AddItem()
+	item2.AddText("Item 2", domain.TextMode{})
+	item2.AddParagraph().AddText("More about item 2.", domain.TextMode{})
+	item2.AddCodeBlock("", "x = 12\n")
+
+	require.Len(t, issues, 1)
+	require.Equal(t, expected, issues[0].Description)
+}
+
+func TestMarkdownParserDescriptionTaskLists(t *testing.T) {
+	input := `[Story] Story title
+
+- [ ] Log in
+- [x] **Log** out
+    - [X] Nested
+
+1. [ ] Ordered
+2. Not a task
+
+Labels: a
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	tasks := expected.AddTaskList()
+	tasks.AddItem().AddText("Log in", domain.TextMode{})
+	item := tasks.AddItem()
+	item.IsChecked = true
+	item.AddText("Log", domain.TextMode{Bold: true})
+	item.AddText(" out", domain.TextMode{})
+	nested := item.AddTaskList().AddItem()
+	nested.IsChecked = true
+	nested.AddText("Nested", domain.TextMode{})
+	list := expected.AddOrderedList()
+	list.AddItem().AddText("[ ] Ordered", domain.TextMode{})
+	list.AddItem().AddText("Not a task", domain.TextMode{})
+
+	require.Len(t, issues, 1)
+	require.Equal(t, expected, issues[0].Description)
+}
+
+func TestMarkdownParserDescriptionQuotes(t *testing.T) {
+	input := `[Bug] Bug title
+
+> The export fails:
+>
+> > Nested quote
+
+Then:
+
+> [!WARNING]
+> Do not retry.
+
+Panels may be empty:
+
+> [!note]
+
+Epic: 123
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	quote := expected.AddBlockQuote()
+	quote.AddParagraph().AddText("The export fails:", domain.TextMode{})
+	quote.AddBlockQuote().AddParagraph().AddText(
+		"Nested quote", domain.TextMode{},
+	)
+	expected.AddParagraph().AddText("Then:", domain.TextMode{})
+	expected.AddPanel(domain.PanelTypeWarning).AddParagraph().AddText(
+		"Do not retry.", domain.TextMode{},
+	)
+	expected.AddParagraph().AddText("Panels may be empty:", domain.TextMode{})
+	expected.AddPanel(domain.PanelTypeInfo)
+
+	require.Len(t, issues, 1)
+	require.Equal(t, expected, issues[0].Description)
+}
+
+func TestMarkdownParserDescriptionTables(t *testing.T) {
+	input := `[Bug] Bug title
+
+| Step | Expected | Actual |
+| :--- | :------: | -----: |
+| Log in | **Home** page | ` + "`500`" + ` \| error |
+| Log out | | |
+
+|  |  |
+| --- | --- |
+| No | header |
+
+Epic: 123
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	table := expected.AddTable(
+		domain.TableAlignmentLeft,
+		domain.TableAlignmentCenter,
+		domain.TableAlignmentRight,
+	)
+	header := table.AddHeaderRow()
+	header.AddCell().AddText("Step", domain.TextMode{})
+	header.AddCell().AddText("Expected", domain.TextMode{})
+	header.AddCell().AddText("Actual", domain.TextMode{})
+	row := table.AddRow()
+	row.AddCell().AddText("Log in", domain.TextMode{})
+	cell := row.AddCell()
+	cell.AddText("Home", domain.TextMode{Bold: true})
+	cell.AddText(" page", domain.TextMode{})
+	cell = row.AddCell()
+	cell.AddText("500", domain.TextMode{Code: true})
+	cell.AddText(" | error", domain.TextMode{})
+	row = table.AddRow()
+	row.AddCell().AddText("Log out", domain.TextMode{})
+	row.AddCell()
+	row.AddCell()
+	table = expected.AddTable(domain.TableAlignmentLeft, domain.TableAlignmentLeft)
+	row = table.AddRow()
+	row.AddCell().AddText("No", domain.TextMode{})
+	row.AddCell().AddText("header", domain.TextMode{})
+
+	require.Len(t, issues, 1)
+	require.Equal(t, expected, issues[0].Description)
+}
+
+func TestMarkdownParserDescriptionImages(t *testing.T) {
+	input := `[Bug] Bug title
+
+See ![the \[error\]](./img/bug.png) and [![logo](https://e.com/logo.png)](https://e.com).
+
+Epic: 123
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	expected.AddParagraph().AddText("See", domain.TextMode{})
+	expected.AddImage("./img/bug.png", "the [error]")
+	p := expected.AddParagraph()
+	p.AddText("and ", domain.TextMode{})
+	p.AddLink("logo", "https://e.com", domain.TextMode{})
+	p.AddText(".", domain.TextMode{})
+
+	require.Len(t, issues, 1)
+	require.Equal(t, expected, issues[0].Description)
+}
+
+func TestMarkdownParserDescriptionWithoutFooter(t *testing.T) {
+	input := `[Bug] Bug title
+
+Hello world.
+`
+	issues, err := markdown.ParseImportFile(
+		strings.NewReader(input),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+	require.Equal(
+		t, &domain.Document{
+			Nodes: []domain.DocumentNode{
+				{
+					Type: domain.DocumentNodeTypeParagraph,
+					ParagraphData: &domain.ParagraphData{
+						Content: domain.TextContainer{
+							Elements: []domain.TextElement{
+								{Text: "Hello world."},
+							},
+						},
+					},
+				},
+			},
+		}, issues[0].Description,
+	)
+}
+
+/******************************************************************************
+ * Round trip through the markdown renderer
+ *****************************************************************************/
+
+// randomDocument generates documents in the form the parser produces them:
+// text is never empty, emphasised text does not start or end with
+// whitespace, and lines do not start with whitespace.
+type randomDocument struct {
+	doc *domain.Document
+}
+
+const randomWordChars = "abcdefghijXYZ0123456789" +
+	"*_[]~<>&\\`#:.-+!()|{}'\"/=@"
+
+func randomWord(r *rand.Rand) string {
+	for {
+		word := ""
+		for i := 0; i < 1+r.Intn(6); i++ {
+			word += string(randomWordChars[r.Intn(len(randomWordChars))])
+		}
+		// a line of =s turns the previous line into a heading
+		if strings.Trim(word, "=") == "" {
+			continue
+		}
+		// the parser reads emphasis closed after a backslash as escaped
+		if strings.HasSuffix(word, "\\") {
+			continue
+		}
+		// a line of tildes after a quote ends the code in it
+		if len(word) >= 3 && strings.Trim(word, "~") == "" {
+			continue
+		}
+		return word
+	}
+}
+
+func randomWords(r *rand.Rand, allowNewLines bool) string {
+	text := randomWord(r)
+	for i := 0; i < r.Intn(4); i++ {
+		if allowNewLines && r.Intn(4) == 0 {
+			text += "\n"
+		} else {
+			text += " "
+		}
+		text += randomWord(r)
+	}
+	return text
+}
+
+var randomLinkURLs = []string{
+	"https://google.com",
+	"https://example.com/a_b?c=d&e=f",
+	"https://example.com/path with spaces",
+}
+
+// quotedCodeReplacer replaces the runs of backticks and tildes which would
+// start code blocks in quotes.
+var quotedCodeReplacer = strings.NewReplacer("```", "``'", "~~~", "~~'")
+
+var randomColors = []string{"#ff5630", "#36b37e", "#0052cc"}
+
+var randomMentions = []string{"Alice Smith", "bob@example.com", "O'Neil"}
+
+var randomEmojis = []string{"warning", "+1", "100"}
+
+// randomInlineTexts are texts which would be read as mentions, emojis or
+// dates if they were not escaped.
+var randomInlineTexts = []string{
+	"@bob@example.co.uk", "@[Alice]", "a:+1:", ":x:y:", "{date:2026-11-01}",
+	"https://example.com/a", "mailto:bob",
+}
+
+var randomImageSources = []string{
+	"./img/bug.png",
+	"img/screen_shot.png",
+	"https://example.com/path with spaces.png",
+}
+
+func randomTextContainer(
+	r *rand.Rand, tc *domain.TextContainer, inQuote bool,
+) {
+	for i := 0; i < 1+r.Intn(5); i++ {
+		if i > 0 && r.Intn(6) == 0 {
+			tc.AddHardBreak()
+		} else if i > 0 {
+			tc.AddText(" ", domain.TextMode{})
+		}
+
+		switch r.Intn(12) {
+		case 0:
+			tc.AddMention(randomMentions[r.Intn(len(randomMentions))], "")
+			continue
+		case 1:
+			tc.AddEmoji(randomEmojis[r.Intn(len(randomEmojis))])
+			continue
+		case 2:
+			tc.AddDate("2026-11-01")
+			continue
+		}
+
+		mode := domain.TextMode{
+			Bold:          r.Intn(3) == 0,
+			Italics:       r.Intn(3) == 0,
+			Strikethrough: r.Intn(4) == 0,
+			Code:          r.Intn(4) == 0,
+			Underline:     r.Intn(5) == 0,
+			Subscript:     r.Intn(8) == 0,
+			Superscript:   r.Intn(8) == 0,
+		}
+		if r.Intn(5) == 0 {
+			mode.Color = randomColors[r.Intn(len(randomColors))]
+		}
+		isLink := r.Intn(5) == 0
+		text := randomWords(r, !mode.Code)
+		if r.Intn(8) == 0 {
+			text = randomInlineTexts[r.Intn(len(randomInlineTexts))]
+		}
+		if mode.Code && (isLink || mode.Bold || mode.Italics ||
+			mode.Strikethrough) {
+			// the parser ends links at brackets in code and emphasis at
+			// backticks in code
+			text = strings.NewReplacer("[", "(", "]", ")", "`", "'").Replace(text)
+		}
+		if mode.Code {
+			// code fenced with three backticks starts a code block
+			text = strings.Replace(text, "``", "`'", -1)
+		}
+		if mode.Code && inQuote {
+			text = quotedCodeReplacer.Replace(text)
+		}
+		if isLink && r.Intn(3) == 0 {
+			// links to their own URL
+			linkURL := randomLinkURLs[r.Intn(len(randomLinkURLs))]
+			tc.AddLink(linkURL, linkURL, domain.TextMode{})
+		} else if isLink {
+			tc.AddLink(text, randomLinkURLs[r.Intn(len(randomLinkURLs))], mode)
+		} else {
+			tc.AddText(text, mode)
+		}
+	}
+}
+
+// randomTableCell adds text to a table cell. Cells are single lines and
+// their code has no pipes.
+func randomTableCell(r *rand.Rand, tc *domain.TextContainer, inQuote bool) {
+	cell := domain.TextContainer{}
+	randomTextContainer(r, &cell, inQuote)
+	for _, element := range cell.Elements {
+		text := strings.Replace(element.Text, "\n", " ", -1)
+		if element.Mode.Code {
+			text = strings.Replace(text, "|", "!", -1)
+		}
+		if element.LinkURL != "" {
+			tc.AddLink(text, element.LinkURL, element.Mode)
+		} else if element.Type != domain.TextElementTypeText &&
+			element.Type != domain.TextElementTypeHardBreak {
+			tc.Elements = append(tc.Elements, element)
+		} else if element.Type == domain.TextElementTypeHardBreak {
+			tc.AddText(text, domain.TextMode{})
+		} else {
+			tc.AddText(text, element.Mode)
+		}
+	}
+}
+
+func randomTable(r *rand.Rand, doc *domain.Document, inQuote bool) {
+	alignments := make([]domain.TableAlignment, 1+r.Intn(3))
+	for i := range alignments {
+		alignments[i] = domain.TableAlignment(r.Intn(3))
+	}
+	table := doc.AddTable(alignments...)
+	// a header row of empty cells is no header row and a table without text
+	// is dropped, so only the cells below a header row can be empty
+	hasHeader := r.Intn(4) != 0
+	rows := 1 + r.Intn(3)
+	if hasHeader {
+		row := table.AddHeaderRow()
+		for range alignments {
+			randomTableCell(r, row.AddCell(), inQuote)
+		}
+		rows = r.Intn(4)
+	}
+	for i := 0; i < rows; i++ {
+		row := table.AddRow()
+		for range alignments {
+			cell := row.AddCell()
+			if !hasHeader || r.Intn(5) != 0 {
+				randomTableCell(r, cell, inQuote)
+			}
+		}
+	}
+}
+
+// randomBlocks adds blocks to the document. Lists, quotes and expands nest
+// up to maxDepth levels.
+func randomBlocks(
+	r *rand.Rand, doc *domain.Document, maxDepth int, inListItem bool,
+	inQuote bool,
+) {
+	for i := 0; i < 1+r.Intn(6); i++ {
+		// quotes are never empty
+		choice := r.Intn(11)
+		switch {
+		case choice == 0:
+			doc.AddHeading(domain.HeadingLevel(r.Intn(5)), randomWords(r, false))
+		case choice == 1:
+			language := []string{"", "python", "go"}[r.Intn(3)]
+			if inListItem {
+				// the language of nested code blocks is not rendered
+				language = ""
+			}
+			code := randomWords(r, true) + "\n"
+			if inQuote {
+				code = quotedCodeReplacer.Replace(code)
+			}
+			doc.AddCodeBlock(language, code)
+		case choice == 2:
+			var list *domain.ListData
+			switch r.Intn(3) {
+			case 0:
+				list = doc.AddOrderedList()
+			case 1:
+				list = doc.AddUnorderedList()
+			default:
+				list = doc.AddTaskList()
+			}
+			for j := 0; j < 1+r.Intn(4); j++ {
+				item := list.AddItem()
+				item.IsChecked = list.IsTaskList && r.Intn(2) == 0
+				randomTextContainer(r, &item.TextContainer, inQuote)
+				if maxDepth > 1 && r.Intn(3) == 0 {
+					randomBlocks(r, &item.Document, maxDepth-1, true, inQuote)
+				}
+			}
+		case choice == 3 && maxDepth > 1:
+			quote := doc.AddBlockQuote()
+			randomBlocks(r, &quote.Document, maxDepth-1, false, true)
+		case choice == 4 && maxDepth > 1:
+			panel := doc.AddPanel(domain.PanelType(r.Intn(5)))
+			randomBlocks(r, &panel.Document, maxDepth-1, false, true)
+		case choice == 5:
+			randomTable(r, doc, inQuote)
+		case choice == 6:
+			altText := ""
+			if r.Intn(3) != 0 {
+				altText = randomWords(r, false)
+			}
+			doc.AddImage(randomImageSources[r.Intn(len(randomImageSources))], altText)
+		case choice == 7 && maxDepth > 1:
+			title := ""
+			if r.Intn(4) != 0 {
+				title = randomWords(r, false)
+			}
+			expand := doc.AddExpand(title)
+			if r.Intn(4) != 0 {
+				randomBlocks(
+					r, &expand.Document, maxDepth-1, inListItem, inQuote,
+				)
+			}
+		case choice == 8:
+			doc.AddRule()
+		default:
+			randomTextContainer(r, doc.AddParagraph(), inQuote)
+		}
+	}
+}
+
+func (randomDocument) Generate(r *rand.Rand, size int) reflect.Value {
+	doc := &domain.Document{}
+	randomBlocks(r, doc, 3, false, false)
+	return reflect.ValueOf(randomDocument{doc})
+}
+
+func TestMarkdownParserRenderedDocumentRoundTrip(t *testing.T) {
+	err := quick.Check(func(rd randomDocument) bool {
+		markdownFile := "[Story] Title\n\n" + markdown.RenderDocument(rd.doc) + "\n"
+		// rules would separate issues
+		issues, err := markdown.ParseImportFileWithOptions(
+			strings.NewReader(markdownFile),
+			markdown.ParserOptions{Separator: "<!-- issue -->"},
+		)
+		if err != nil || len(issues) != 1 {
+			t.Logf("Failed to parse:\n%s\nerror: %v", markdownFile, err)
+			return false
+		}
+		if !reflect.DeepEqual(rd.doc, issues[0].Description) {
+			t.Logf("Document changed:\n%s", markdownFile)
+			require.Equal(t, rd.doc, issues[0].Description)
+			return false
+		}
+		return true
+	}, &quick.Config{MaxCount: 500})
+	require.NoError(t, err)
+}
@@ -0,0 +1,715 @@
+// Package markdown parses the issues of markdown import files and renders
+// domain documents as CommonMark which the parser reads back to the same
+// documents.
+//
+// A few texts cannot be represented: a line made only of =s turns the line
+// before it into a heading, emphasised text or link text cannot end with a
+// backslash, code in link text cannot contain brackets, emphasised code
+// cannot contain backticks, code at the start of a line cannot contain two
+// backticks in a row and code in quotes cannot contain three backticks or
+// tildes in a row. Code after a quote cannot have a line made only of
+// tildes. Code blocks nested in list items are rendered without their
+// language. Only the first row of a table can be a header row, line breaks
+// in table cells are rendered as spaces and code in table cells cannot
+// contain pipes.
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/glestaris/issuez/domain"
+)
+
+// blockSeparator is rendered between two blocks which the parser would
+// otherwise merge: a list followed by a list of the same kind or a code
+// block, and a quote followed by a quote or, in list items, a code block.
+// It also ends list items whose last nested block is a code block, which
+// the parser would otherwise read as inline code.
+const blockSeparator = "<!-- -->"
+
+// renderer renders the blocks of a document.
+//
+// The parser looks for code fences anywhere in the lines of a quote, even
+// in the middle of a longer fence, and reads everything up to the next line
+// with the same fence as part of the quote. Code blocks in quotes are
+// therefore fenced with tildes, and with shorter fences the deeper the
+// quote is nested.
+type renderer struct {
+	maxQuoteDepth int
+	quoteDepth    int
+
+	// github renders the mentions and the dates as their text
+	github bool
+}
+
+// RenderDocument renders the document as markdown. Blocks are separated by
+// an empty line. A nil document renders as "".
+func RenderDocument(doc *domain.Document) string {
+	if doc == nil {
+		return ""
+	}
+	r := renderer{maxQuoteDepth: quoteDepth(doc.Nodes)}
+	return r.renderBlocks(doc.Nodes)
+}
+
+// RenderGitHubDocument renders the document as markdown for GitHub, which
+// has no syntax for mentions and dates: they are rendered as their text.
+// Emojis, panels and expands are rendered as GitHub reads them anyway.
+func RenderGitHubDocument(doc *domain.Document) string {
+	if doc == nil {
+		return ""
+	}
+	r := renderer{maxQuoteDepth: quoteDepth(doc.Nodes), github: true}
+	return r.renderBlocks(doc.Nodes)
+}
+
+func (r renderer) renderBlocks(nodes []domain.DocumentNode) string {
+	blocks := []string{}
+	for i, node := range nodes {
+		if i > 0 && needsSeparator(nodes[i-1], node) {
+			blocks = append(blocks, blockSeparator)
+		}
+		blocks = append(blocks, r.renderNode(node))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+func isQuote(node domain.DocumentNode) bool {
+	return node.Type == domain.DocumentNodeTypeBlockQuote ||
+		node.Type == domain.DocumentNodeTypePanel
+}
+
+func needsSeparator(a domain.DocumentNode, b domain.DocumentNode) bool {
+	if isQuote(a) &&
+		(isQuote(b) || b.Type == domain.DocumentNodeTypeCodeBlock) {
+		return true
+	}
+	if a.Type != domain.DocumentNodeTypeList {
+		return false
+	}
+	switch b.Type {
+	case domain.DocumentNodeTypeList:
+		return a.ListData.IsOrdered == b.ListData.IsOrdered
+	case domain.DocumentNodeTypeCodeBlock:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r renderer) renderNode(node domain.DocumentNode) string {
+	switch node.Type {
+	case domain.DocumentNodeTypeParagraph:
+		return r.renderText(node.ParagraphData.Content)
+	case domain.DocumentNodeTypeHeading:
+		return renderHeading(node.HeadingData)
+	case domain.DocumentNodeTypeList:
+		return r.renderList(node.ListData)
+	case domain.DocumentNodeTypeCodeBlock:
+		return r.renderCodeBlock(node.CodeBlockData)
+	case domain.DocumentNodeTypeBlockQuote:
+		return renderQuote(r.quoted().renderBlocks(node.BlockQuoteData.Nodes))
+	case domain.DocumentNodeTypePanel:
+		return r.renderPanel(node.PanelData)
+	case domain.DocumentNodeTypeExpand:
+		return r.renderExpand(node.ExpandData)
+	case domain.DocumentNodeTypeTable:
+		return r.renderTable(node.TableData)
+	case domain.DocumentNodeTypeImage:
+		return renderImage(node.ImageData)
+	case domain.DocumentNodeTypeRule:
+		return "---"
+	default:
+		return ""
+	}
+}
+
+func renderHeading(heading *domain.HeadingData) string {
+	// a heading may end with a sequence of #s which is not part of its text
+	text := escapeText(heading.Text, "#")
+	return strings.Repeat("#", int(heading.Level)+1) + " " + text
+}
+
+// listItemBlockIndent is the indentation of the blocks nested in a list
+// item. Paragraphs need to be indented by four spaces to be read as part of
+// the item, whatever the width of its marker.
+const listItemBlockIndent = "    "
+
+func indentLines(text string, indent string) []string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return lines
+}
+
+// listItemBlocks returns the blocks nested in a list item without the
+// language of their code blocks. The parser does not see the end of a list
+// item which has a code block with a language.
+func listItemBlocks(nodes []domain.DocumentNode) []domain.DocumentNode {
+	blocks := make([]domain.DocumentNode, len(nodes))
+	for i, node := range nodes {
+		if node.Type == domain.DocumentNodeTypeCodeBlock &&
+			node.CodeBlockData.Language != "" {
+			node.CodeBlockData = &domain.CodeBlockData{
+				Code: node.CodeBlockData.Code,
+			}
+		}
+		if node.Type == domain.DocumentNodeTypeExpand {
+			node.ExpandData = &domain.ExpandData{
+				Title: node.ExpandData.Title,
+				Document: domain.Document{
+					Nodes: listItemBlocks(node.ExpandData.Nodes),
+				},
+			}
+		}
+		blocks[i] = node
+	}
+	return blocks
+}
+
+func (r renderer) renderList(list *domain.ListData) string {
+	lines := []string{}
+	for i, item := range list.Items {
+		marker := "- "
+		if list.IsTaskList && item.IsChecked {
+			marker = "- [x] "
+		} else if list.IsTaskList {
+			marker = "- [ ] "
+		} else if list.IsOrdered {
+			marker = fmt.Sprintf("%d. ", i+1)
+		}
+		indent := strings.Repeat(" ", len(marker))
+		if list.IsTaskList {
+			// the checkbox is part of the text of the item
+			indent = "  "
+		}
+		text := r.renderText(item.TextContainer)
+		if list.IsTaskList && strings.HasPrefix(text, "(") {
+			// the checkbox and the parenthesis would make a link
+			text = "\\" + text
+		}
+		itemLines := strings.Split(text, "\n")
+		for j, itemLine := range itemLines {
+			if j == 0 {
+				lines = append(lines, marker+itemLine)
+			} else {
+				lines = append(lines, indent+itemLine)
+			}
+		}
+
+		if len(item.Nodes) != 0 {
+			blocks := r.renderBlocks(listItemBlocks(item.Nodes))
+			lastNode := item.Nodes[len(item.Nodes)-1]
+			if lastNode.Type == domain.DocumentNodeTypeCodeBlock {
+				blocks += "\n\n" + blockSeparator
+			}
+			lines = append(lines, "")
+			lines = append(lines, indentLines(blocks, listItemBlockIndent)...)
+			if i < len(list.Items)-1 {
+				// the next item would continue the last nested block
+				lines = append(lines, "")
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// quoted returns the renderer of the blocks in a quote.
+func (r renderer) quoted() renderer {
+	r.quoteDepth++
+	return r
+}
+
+func renderQuote(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// calloutNames are the GitHub callouts the panels are rendered as.
+var calloutNames = map[domain.PanelType]string{
+	domain.PanelTypeInfo:    "NOTE",
+	domain.PanelTypeSuccess: "TIP",
+	domain.PanelTypeNote:    "IMPORTANT",
+	domain.PanelTypeWarning: "WARNING",
+	domain.PanelTypeError:   "CAUTION",
+}
+
+func (r renderer) renderPanel(panel *domain.PanelData) string {
+	text := "[!" + calloutNames[panel.PanelType] + "]"
+	if len(panel.Nodes) != 0 {
+		text += "\n\n" + r.quoted().renderBlocks(panel.Nodes)
+	}
+	return renderQuote(text)
+}
+
+// renderExpand renders the expand as a <details> block with its title as
+// the summary. The tags are separated from the blocks by blank lines, so
+// that the blocks are parsed as markdown.
+func (r renderer) renderExpand(expand *domain.ExpandData) string {
+	text := "<details>\n<summary>" + EscapeText(expand.Title) + "</summary>"
+	if len(expand.Nodes) != 0 {
+		text += "\n\n" + r.renderBlocks(expand.Nodes)
+	}
+	return text + "\n\n</details>"
+}
+
+// renderTable renders the table with a row per line. A table without a
+// header row is rendered with a header row of empty cells, which the parser
+// does not keep.
+func (r renderer) renderTable(table *domain.TableData) string {
+	columns := len(table.Alignments)
+	for _, row := range table.Rows {
+		if len(row.Cells) > columns {
+			columns = len(row.Cells)
+		}
+	}
+	if columns == 0 {
+		columns = 1
+	}
+
+	rows := table.Rows
+	header := domain.TableRow{}
+	if len(rows) != 0 && rows[0].IsHeader {
+		header = rows[0]
+		rows = rows[1:]
+	}
+
+	delimiters := make([]string, columns)
+	for i := range delimiters {
+		switch table.Alignment(i) {
+		case domain.TableAlignmentCenter:
+			delimiters[i] = ":-:"
+		case domain.TableAlignmentRight:
+			delimiters[i] = "--:"
+		default:
+			delimiters[i] = "---"
+		}
+	}
+
+	lines := []string{
+		r.renderTableRow(header, columns),
+		"| " + strings.Join(delimiters, " | ") + " |",
+	}
+	for _, row := range rows {
+		lines = append(lines, r.renderTableRow(row, columns))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r renderer) renderTableRow(row domain.TableRow, columns int) string {
+	cells := make([]string, columns)
+	for i, cell := range row.Cells {
+		// the cells of a row are on a single line
+		cells[i] = strings.Replace(
+			r.renderText(softBreaks(cell)), "\n", " ", -1,
+		)
+	}
+	return "| " + strings.Join(cells, " | ") + " |"
+}
+
+// softBreaks returns the text with its hard breaks turned into line breaks
+// of the text.
+func softBreaks(tc domain.TextContainer) domain.TextContainer {
+	soft := domain.TextContainer{}
+	for _, element := range tc.Elements {
+		switch {
+		case element.LinkURL != "":
+			soft.AddLink(element.Text, element.LinkURL, element.Mode)
+		case element.Type == domain.TextElementTypeText ||
+			element.Type == domain.TextElementTypeHardBreak:
+			soft.AddText(element.Text, element.Mode)
+		default:
+			soft.Elements = append(soft.Elements, element)
+		}
+	}
+	return soft
+}
+
+// altTextReplacer escapes the alt text of images, which the parser reads as
+// plain text.
+var altTextReplacer = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+
+func renderImage(image *domain.ImageData) string {
+	return "![" + altTextReplacer.Replace(image.AltText) + "](" +
+		renderLinkURL(image.Source) + ")"
+}
+
+var backtickRunRe = regexp.MustCompile("`+")
+
+func longestBacktickRun(text string) int {
+	longest := 0
+	for _, run := range backtickRunRe.FindAllString(text, -1) {
+		if len(run) > longest {
+			longest = len(run)
+		}
+	}
+	return longest
+}
+
+// quoteDepth returns the number of levels of quotes nested in the blocks.
+func quoteDepth(nodes []domain.DocumentNode) int {
+	depth := 0
+	for _, node := range nodes {
+		nodeDepth := 0
+		switch node.Type {
+		case domain.DocumentNodeTypeList:
+			for _, item := range node.ListData.Items {
+				if itemDepth := quoteDepth(item.Nodes); itemDepth > nodeDepth {
+					nodeDepth = itemDepth
+				}
+			}
+		case domain.DocumentNodeTypeBlockQuote:
+			nodeDepth = 1 + quoteDepth(node.BlockQuoteData.Nodes)
+		case domain.DocumentNodeTypePanel:
+			nodeDepth = 1 + quoteDepth(node.PanelData.Nodes)
+		case domain.DocumentNodeTypeExpand:
+			nodeDepth = quoteDepth(node.ExpandData.Nodes)
+		}
+		if nodeDepth > depth {
+			depth = nodeDepth
+		}
+	}
+	return depth
+}
+
+func (r renderer) renderCodeBlock(codeBlock *domain.CodeBlockData) string {
+	fence := ""
+	if r.quoteDepth > 0 {
+		fence = strings.Repeat("~", 3+r.maxQuoteDepth-r.quoteDepth)
+	} else {
+		fenceLen := longestBacktickRun(codeBlock.Code) + 1
+		if fenceLen < 3 {
+			fenceLen = 3
+		}
+		fence = strings.Repeat("`", fenceLen)
+	}
+
+	code := codeBlock.Code
+	if !strings.HasSuffix(code, "\n") {
+		code += "\n"
+	}
+	return fence + codeBlock.Language + "\n" + code + fence
+}
+
+/******************************************************************************
+ * Text
+ *****************************************************************************/
+
+// lineStartEscapeRe matches the text at the start of a line which would
+// otherwise start a block (heading, quote, list) or, in a list item, a new
+// paragraph.
+var lineStartEscapeRe = regexp.MustCompile(`(?m)^([ \t]*)([#>+:\-]|\d+\.)`)
+
+// footerKeyRe matches the text which would make a paragraph be read as the
+// footer of an issue.
+var footerKeyRe = regexp.MustCompile(`(E|Epic|L|Labels):`)
+
+func escapeLineStart(m string) string {
+	trimmed := strings.TrimLeft(m, " \t")
+	indent := m[:len(m)-len(trimmed)]
+	if strings.HasSuffix(trimmed, ".") {
+		// ordered list marker
+		return indent + trimmed[:len(trimmed)-1] + `\.`
+	}
+	return indent + `\` + trimmed
+}
+
+// emojiLikeRe, dateLikeRe and mentionLikeRe match the text which the parser
+// would read as an emoji, a date or a mention of an email address.
+// urlSchemeRe matches the text ending in the scheme of a URL which the
+// parser would turn into a link.
+var (
+	emojiLikeRe   = regexp.MustCompile(`^:[a-z0-9_+-]+:`)
+	dateLikeRe    = regexp.MustCompile(`^\{date:`)
+	mentionLikeRe = regexp.MustCompile(`^@[\w.%+-]+@[\w-]+\.`)
+	urlSchemeRe   = regexp.MustCompile(`(?i)(?:https?|ftp|file|mailto)$`)
+)
+
+// inlineNodeEscapes returns the positions of the characters to escape so
+// that the text is not read as emojis, dates, mentions or links: the colon
+// starting an emoji or ending the scheme of a URL, the brace starting a date
+// and the first dot of the domain of a mentioned email address. Mentions of
+// names start with a bracket, which is escaped anyway.
+func inlineNodeEscapes(text string) map[int]bool {
+	escapes := map[int]bool{}
+	for i, r := range text {
+		switch r {
+		case ':':
+			if emojiLikeRe.MatchString(text[i:]) ||
+				urlSchemeRe.MatchString(text[:i]) {
+				escapes[i] = true
+			}
+		case '{':
+			if dateLikeRe.MatchString(text[i:]) {
+				escapes[i] = true
+			}
+		case '@':
+			if loc := mentionLikeRe.FindStringIndex(text[i:]); loc != nil {
+				escapes[i+loc[1]-1] = true
+			}
+		}
+	}
+	return escapes
+}
+
+// EscapeText escapes the characters of the text which markdown would read as
+// formatting.
+func EscapeText(text string) string {
+	return escapeText(text, "")
+}
+
+// escapeText escapes the text as EscapeText does. The characters in extra
+// are escaped too.
+func escapeText(text string, extra string) string {
+	escaped := strings.Builder{}
+	escapes := inlineNodeEscapes(text)
+	for i, r := range text {
+		if strings.ContainsRune("\\`*_[]~<>|", r) ||
+			strings.ContainsRune(extra, r) || escapes[i] {
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	escapedText := lineStartEscapeRe.ReplaceAllStringFunc(
+		escaped.String(), escapeLineStart,
+	)
+	escapedText = footerKeyRe.ReplaceAllString(escapedText, `$1\:`)
+	// a paragraph starting with [text]: is a link reference definition
+	return strings.Replace(escapedText, `\]:`, `\]\:`, -1)
+}
+
+func renderCode(text string) string {
+	fence := strings.Repeat("`", longestBacktickRun(text)+1)
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		return fence + " " + text + " " + fence
+	}
+	return fence + text + fence
+}
+
+func renderLinkURL(linkURL string) string {
+	if strings.ContainsAny(linkURL, " ()<>") {
+		return "<" + strings.Replace(linkURL, ">", "%3E", -1) + ">"
+	}
+	return linkURL
+}
+
+// textMark is an emphasis mark, or an HTML tag for the formatting markdown
+// cannot express.
+type textMark struct {
+	open  string
+	close string
+}
+
+// textMarks returns the marks of the mode in the order they are opened.
+func textMarks(mode domain.TextMode) []textMark {
+	marks := []textMark{}
+	if mode.Bold {
+		marks = append(marks, textMark{"**", "**"})
+	}
+	if mode.Italics {
+		marks = append(marks, textMark{"_", "_"})
+	}
+	if mode.Strikethrough {
+		marks = append(marks, textMark{"~~", "~~"})
+	}
+	if mode.Underline {
+		marks = append(marks, textMark{"<u>", "</u>"})
+	}
+	if mode.Subscript {
+		marks = append(marks, textMark{"<sub>", "</sub>"})
+	}
+	if mode.Superscript {
+		marks = append(marks, textMark{"<sup>", "</sup>"})
+	}
+	if mode.Color != "" {
+		marks = append(marks, textMark{
+			`<span style="color: ` + mode.Color + `">`, "</span>",
+		})
+	}
+	return marks
+}
+
+func hasTextMark(marks []textMark, mark textMark) bool {
+	for _, m := range marks {
+		if m == mark {
+			return true
+		}
+	}
+	return false
+}
+
+// markStack keeps the marks which are open, in the order they were opened.
+type markStack []textMark
+
+// close closes the open marks which are not marks of the mode. Marks opened
+// after them are closed as well.
+func (s *markStack) close(out *strings.Builder, mode domain.TextMode) {
+	marks := textMarks(mode)
+	closeFrom := len(*s)
+	for i, mark := range *s {
+		if !hasTextMark(marks, mark) {
+			closeFrom = i
+			break
+		}
+	}
+	for i := len(*s) - 1; i >= closeFrom; i-- {
+		out.WriteString((*s)[i].close)
+	}
+	*s = (*s)[:closeFrom]
+}
+
+// open opens the marks of the mode which are not open yet.
+func (s *markStack) open(out *strings.Builder, mode domain.TextMode) {
+	for _, mark := range textMarks(mode) {
+		if !hasTextMark(*s, mark) {
+			out.WriteString(mark.open)
+			*s = append(*s, mark)
+		}
+	}
+}
+
+// autolinkRe matches the URLs which can be written in angle brackets.
+var autolinkRe = regexp.MustCompile(
+	`^(?i)(?:https?|ftp|file)://[^\s<>'"\\]+$`,
+)
+
+// isAutolink checks whether the element is a plain link to its own URL,
+// which is written as the URL in angle brackets.
+func isAutolink(element domain.TextElement) bool {
+	return element.Text == element.LinkURL &&
+		element.Mode == (domain.TextMode{}) &&
+		autolinkRe.MatchString(element.LinkURL)
+}
+
+func renderLink(text string, linkURL string, mode domain.TextMode) string {
+	// the parser does not read emphasis around links with marker characters
+	// in their URL, so the emphasis goes inside the link text
+	out := strings.Builder{}
+	linkMarks := markStack{}
+	out.WriteString("[")
+	linkMarks.open(&out, mode)
+	out.WriteString(text)
+	linkMarks.close(&out, domain.TextMode{})
+	out.WriteString("](")
+	out.WriteString(renderLinkURL(linkURL))
+	out.WriteString(")")
+	return out.String()
+}
+
+// renderInlineNode renders the elements which are not text: hard breaks,
+// mentions, emojis and dates.
+func renderInlineNode(element domain.TextElement) string {
+	switch element.Type {
+	case domain.TextElementTypeHardBreak:
+		return "\\\n"
+	case domain.TextElementTypeMention:
+		return "@[" + element.Text + "]"
+	case domain.TextElementTypeEmoji:
+		return ":" + element.Text + ":"
+	case domain.TextElementTypeDate:
+		return "{date:" + element.Text + "}"
+	default:
+		return ""
+	}
+}
+
+// renderText renders the text elements as inline markdown.
+func (r renderer) renderText(tc domain.TextContainer) string {
+	if r.github {
+		tc = plainText(tc)
+	}
+	return RenderTextContainer(tc)
+}
+
+// plainText returns the text with its mentions and dates turned into text.
+func plainText(tc domain.TextContainer) domain.TextContainer {
+	plain := domain.TextContainer{}
+	for _, element := range tc.Elements {
+		switch element.Type {
+		case domain.TextElementTypeMention, domain.TextElementTypeDate:
+			plain.AddText(element.Text, domain.TextMode{})
+		default:
+			plain.Elements = append(plain.Elements, element)
+		}
+	}
+	return plain
+}
+
+// RenderTextContainer renders the text elements as inline markdown.
+// Emphasis is opened and closed only where the formatting changes, and
+// whitespace at the edges of emphasised text is moved outside of it.
+func RenderTextContainer(tc domain.TextContainer) string {
+	out := strings.Builder{}
+	openMarks := markStack{}
+	pendingSpace := ""
+	afterMention := false
+
+	for _, element := range tc.Elements {
+		if element.Type != domain.TextElementTypeText {
+			openMarks.close(&out, domain.TextMode{})
+			out.WriteString(pendingSpace)
+			out.WriteString(renderInlineNode(element))
+			pendingSpace = ""
+			afterMention = element.Type == domain.TextElementTypeMention
+			continue
+		}
+
+		core := strings.TrimLeft(element.Text, " \t\n")
+		leadingSpace := element.Text[:len(element.Text)-len(core)]
+		trimmed := strings.TrimRight(core, " \t\n")
+		trailingSpace := core[len(trimmed):]
+		core = trimmed
+
+		if core == "" {
+			// whitespace cannot be emphasised
+			openMarks.close(&out, element.Mode)
+			pendingSpace += element.Text
+			continue
+		}
+
+		text := EscapeText(core)
+		if element.Mode.Code {
+			text = renderCode(core)
+		} else if afterMention && element.LinkURL == "" &&
+			len(textMarks(element.Mode)) == 0 &&
+			strings.HasPrefix(text, "(") {
+			// the mention and the parenthesis would make a link, even with
+			// whitespace between them
+			text = "\\" + text
+		}
+		afterMention = false
+		if isAutolink(element) {
+			openMarks.close(&out, domain.TextMode{})
+			text = "<" + element.LinkURL + ">"
+		} else if element.LinkURL != "" {
+			openMarks.close(&out, domain.TextMode{})
+			text = renderLink(text, element.LinkURL, element.Mode)
+		} else {
+			openMarks.close(&out, element.Mode)
+		}
+		out.WriteString(pendingSpace)
+		out.WriteString(leadingSpace)
+		if element.LinkURL == "" {
+			openMarks.open(&out, element.Mode)
+		}
+		out.WriteString(text)
+		pendingSpace = trailingSpace
+	}
+
+	openMarks.close(&out, domain.TextMode{})
+	out.WriteString(pendingSpace)
+
+	return out.String()
+}
@@ -0,0 +1,262 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/markdown"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderDocument(t *testing.T) {
+	doc := &domain.Document{}
+	doc.AddHeading(domain.HeadingLevel2, "Issue #12")
+	p := doc.AddParagraph()
+	p.AddText("Some ", domain.TextMode{})
+	p.AddText("bold ", domain.TextMode{Bold: true})
+	p.AddText("and italics", domain.TextMode{Bold: true, Italics: true})
+	p.AddText(" and ", domain.TextMode{})
+	p.AddLink("a link", "https://google.com", domain.TextMode{Italics: true})
+	doc.AddUnorderedList().AddItem().AddText("One", domain.TextMode{})
+	doc.AddUnorderedList().AddItem().AddText("Two", domain.TextMode{})
+	doc.AddCodeBlock("go", "x := \"```\"")
+
+	require.Equal(t, "## Issue \\#12\n\n"+
+		"Some **bold _and italics_** and [_a link_](https://google.com)\n\n"+
+		"- One\n\n"+
+		"<!-- -->\n\n"+
+		"- Two\n\n"+
+		"<!-- -->\n\n"+
+		"````go\n"+
+		"x := \"```\"\n"+
+		"````", markdown.RenderDocument(doc))
+}
+
+func TestRenderDocumentNestedLists(t *testing.T) {
+	doc := &domain.Document{}
+	list := doc.AddOrderedList()
+	item := list.AddItem()
+	item.AddText("One", domain.TextMode{})
+	item.AddUnorderedList().AddItem().AddText("Nested", domain.TextMode{})
+	item.AddCodeBlock("go", "x := 12\n")
+	list.AddItem().AddText("Two", domain.TextMode{})
+
+	require.Equal(t, "1. One\n\n"+
+		"    - Nested\n\n"+
+		"    <!-- -->\n\n"+
+		"    ```\n"+
+		"    x := 12\n"+
+		"    ```\n\n"+
+		"    <!-- -->\n\n"+
+		"2. Two", markdown.RenderDocument(doc))
+}
+
+func TestRenderDocumentTaskLists(t *testing.T) {
+	doc := &domain.Document{}
+	tasks := doc.AddTaskList()
+	tasks.AddItem().AddText("(optional) Log in", domain.TextMode{})
+	item := tasks.AddItem()
+	item.IsChecked = true
+	item.AddText("Log\nout", domain.TextMode{})
+	item.AddTaskList().AddItem().AddText("Nested", domain.TextMode{})
+	doc.AddUnorderedList().AddItem().AddText("[ ] Not a task", domain.TextMode{})
+
+	require.Equal(t, "- [ ] \\(optional) Log in\n"+
+		"- [x] Log\n"+
+		"  out\n\n"+
+		"    - [ ] Nested\n\n"+
+		"<!-- -->\n\n"+
+		"- \\[ \\] Not a task", markdown.RenderDocument(doc))
+}
+
+func TestRenderDocumentQuotes(t *testing.T) {
+	doc := &domain.Document{}
+	quote := doc.AddBlockQuote()
+	quote.AddParagraph().AddText("Quoted", domain.TextMode{})
+	quote.AddCodeBlock("go", "x := 12\n")
+	panel := doc.AddPanel(domain.PanelTypeWarning)
+	nested := panel.AddBlockQuote()
+	nested.AddCodeBlock("", "y := 13\n")
+
+	require.Equal(t, "> Quoted\n"+
+		">\n"+
+		"> ~~~~go\n"+
+		"> x := 12\n"+
+		"> ~~~~\n\n"+
+		"<!-- -->\n\n"+
+		"> [!WARNING]\n"+
+		">\n"+
+		"> > ~~~\n"+
+		"> > y := 13\n"+
+		"> > ~~~", markdown.RenderDocument(doc))
+}
+
+func TestRenderDocumentExpands(t *testing.T) {
+	doc := &domain.Document{}
+	expand := doc.AddExpand("Stack *trace*")
+	expand.AddCodeBlock("", "panic: oops\n")
+	doc.AddExpand("")
+
+	require.Equal(t, "<details>\n"+
+		"<summary>Stack \\*trace\\*</summary>\n\n"+
+		"```\n"+
+		"panic: oops\n"+
+		"```\n\n"+
+		"</details>\n\n"+
+		"<details>\n"+
+		"<summary></summary>\n\n"+
+		"</details>", markdown.RenderDocument(doc))
+}
+
+func TestRenderDocumentTables(t *testing.T) {
+	doc := &domain.Document{}
+	table := doc.AddTable(domain.TableAlignmentCenter)
+	table.AddHeaderRow().AddCell().AddText("A | B", domain.TextMode{})
+	table.AddRow().AddCell().AddText("Line\nbreak", domain.TextMode{Bold: true})
+	table.AddRow().AddCell()
+	table = doc.AddTable()
+	row := table.AddRow()
+	row.AddCell().AddText("No", domain.TextMode{})
+	row.AddCell().AddText("header", domain.TextMode{})
+
+	require.Equal(t, "| A \\| B |\n"+
+		"| :-: |\n"+
+		"| **Line break** |\n"+
+		"|  |\n\n"+
+		"|  |  |\n"+
+		"| --- | --- |\n"+
+		"| No | header |", markdown.RenderDocument(doc))
+}
+
+func TestRenderDocumentTableHardBreaks(t *testing.T) {
+	doc := &domain.Document{}
+	cell := doc.AddTable().AddRow().AddCell()
+	cell.AddText("Hard", domain.TextMode{Underline: true})
+	cell.AddHardBreak()
+	cell.AddText("break", domain.TextMode{})
+
+	require.Equal(t, "|  |\n"+
+		"| --- |\n"+
+		"| <u>Hard</u> break |", markdown.RenderDocument(doc))
+}
+
+func TestRenderDocumentImages(t *testing.T) {
+	doc := &domain.Document{}
+	doc.AddImage("./img/bug.png", "the [error]")
+	doc.AddImage("https://e.com/a b.png", "")
+
+	require.Equal(t, "![the \\[error\\]](./img/bug.png)\n\n"+
+		"![](<https://e.com/a b.png>)", markdown.RenderDocument(doc))
+}
+
+func TestRenderDocumentNil(t *testing.T) {
+	require.Equal(t, "", markdown.RenderDocument(nil))
+}
+
+func TestRenderGitHubDocument(t *testing.T) {
+	doc := &domain.Document{}
+	p := doc.AddParagraph()
+	p.AddText("Ask ", domain.TextMode{Bold: true})
+	p.AddMention("Alice Smith", "5b10ac8d82e05b22cc7d4ef5")
+	p.AddText(" by ", domain.TextMode{})
+	p.AddDate("2026-11-01")
+	p.AddText(" ", domain.TextMode{})
+	p.AddEmoji("warning")
+	doc.AddTable().AddRow().AddCell().AddMention("Bob", "")
+	doc.AddExpand("Logs").AddParagraph().AddText("panic", domain.TextMode{})
+
+	require.Equal(t, `**Ask** Alice Smith by 2026-11-01 :warning:
+
+|  |
+| --- |
+| Bob |
+
+<details>
+<summary>Logs</summary>
+
+panic
+
+</details>`, markdown.RenderGitHubDocument(doc))
+	require.Equal(t, "", markdown.RenderGitHubDocument(nil))
+}
+
+func TestRenderTextContainer(t *testing.T) {
+	tc := domain.TextContainer{}
+	tc.AddText("`code`", domain.TextMode{Code: true})
+	tc.AddText(" ", domain.TextMode{})
+	tc.AddLink("spaces", "https://e.com/a b", domain.TextMode{})
+	tc.AddText("\n1. not a list", domain.TextMode{})
+	require.Equal(
+		t,
+		"`` `code` `` [spaces](<https://e.com/a b>)\n1\\. not a list",
+		markdown.RenderTextContainer(tc),
+	)
+}
+
+func TestRenderTextContainerHTMLMarks(t *testing.T) {
+	tc := domain.TextContainer{}
+	tc.AddText("H", domain.TextMode{})
+	tc.AddText("2", domain.TextMode{Subscript: true})
+	tc.AddText("O ", domain.TextMode{})
+	tc.AddText("red ", domain.TextMode{Bold: true, Color: "#ff5630"})
+	tc.AddText("underlined", domain.TextMode{Bold: true, Underline: true})
+	tc.AddHardBreak()
+	tc.AddText("x", domain.TextMode{Superscript: true})
+	require.Equal(
+		t,
+		`H<sub>2</sub>O **<span style="color: #ff5630">red</span> `+
+			"<u>underlined</u>**\\\n<sup>x</sup>",
+		markdown.RenderTextContainer(tc),
+	)
+}
+
+func TestRenderTextContainerInlineNodes(t *testing.T) {
+	tc := domain.TextContainer{}
+	tc.AddText("Ask ", domain.TextMode{Bold: true})
+	tc.AddMention("Alice Smith", "5b10ac8d82e05b22cc7d4ef5")
+	tc.AddText("(QA) ", domain.TextMode{})
+	tc.AddEmoji("warning")
+	tc.AddText(" by ", domain.TextMode{})
+	tc.AddDate("2026-11-01")
+	require.Equal(
+		t,
+		"**Ask** @[Alice Smith]\\(QA) :warning: by {date:2026-11-01}",
+		markdown.RenderTextContainer(tc),
+	)
+}
+
+func TestRenderTextContainerAutolinks(t *testing.T) {
+	tc := domain.TextContainer{}
+	tc.AddLink(
+		"https://example.com/a", "https://example.com/a", domain.TextMode{},
+	)
+	tc.AddText(" ", domain.TextMode{})
+	tc.AddLink(
+		"https://example.com/b", "https://example.com/b",
+		domain.TextMode{Bold: true},
+	)
+	tc.AddText(" https://example.com/c", domain.TextMode{})
+	require.Equal(
+		t,
+		"<https://example.com/a> [**https\\://example.com/b**]"+
+			"(https://example.com/b) https\\://example.com/c",
+		markdown.RenderTextContainer(tc),
+	)
+}
+
+func TestEscapeText(t *testing.T) {
+	require.Equal(
+		t,
+		`\*a\_b\* \[c\]\: \~d\~ \<e\>`,
+		markdown.EscapeText("*a_b* [c]: ~d~ <e>"),
+	)
+	require.Equal(t, "\\# a\n\\- b\n\\> c", markdown.EscapeText("# a\n- b\n> c"))
+	require.Equal(t, `Epic\: TEST-1`, markdown.EscapeText("Epic: TEST-1"))
+	require.Equal(
+		t,
+		`@bob@example\.com \:warning: 12:30 \{date:2026-11-01}`,
+		markdown.EscapeText(
+			"@bob@example.com :warning: 12:30 {date:2026-11-01}",
+		),
+	)
+}
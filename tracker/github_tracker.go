@@ -0,0 +1,384 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/github"
+	"github.com/glestaris/issuez/markdown"
+)
+
+// APP layer
+//  Test using a fake GitHub API server
+
+// githubParentRe matches the epics which are parent tracking issues, such as
+// "#12", rather than milestones.
+var githubParentRe = regexp.MustCompile(`^#?(\d+)$`)
+
+func init() {
+	RegisterBackend(Backend{
+		Type:        "github",
+		Description: "GitHub Issues, through its REST API",
+		Settings: []Setting{
+			{
+				Key:  "apiHost",
+				Type: SettingTypeString,
+				Description: "The URL of the GitHub API, " +
+					"https://<host>/api/v3 for GitHub Enterprise",
+				Default: github.DefaultAPIHost,
+			},
+			{
+				Key:         "apiToken",
+				Type:        SettingTypeString,
+				Required:    true,
+				Description: "The personal access token of the account",
+			},
+			{
+				Key:  "repository",
+				Type: SettingTypeString,
+				Description: "The repository issues are imported in, " +
+					"as owner/name",
+			},
+			{
+				Key:         "bugLabel",
+				Type:        SettingTypeString,
+				Description: "The label of bugs",
+				Default:     "bug",
+			},
+			{
+				Key:         "storyLabel",
+				Type:        SettingTypeString,
+				Description: "The label of user stories",
+				Default:     "enhancement",
+			},
+			{
+				Key:         "choreLabel",
+				Type:        SettingTypeString,
+				Description: "The label of chores",
+				Default:     "chore",
+			},
+			{
+				Key:         "releaseLabel",
+				Type:        SettingTypeString,
+				Description: "The label of releases",
+				Default:     "release",
+			},
+			{
+				Key:  "requestTimeout",
+				Type: SettingTypeDuration,
+				Description: "The timeout of each request " +
+					"(0 for no timeout)",
+			},
+		},
+		New: func(config Config) (TrackerService, error) {
+			repository := config.String("repository")
+			parts := strings.Split(repository, "/")
+			if repository != "" &&
+				(len(parts) != 2 || parts[0] == "" || parts[1] == "") {
+				return nil, fmt.Errorf(
+					"Invalid repository '%s', expected owner/name",
+					repository,
+				)
+			}
+			return newGitHubTrackerService(
+				config.String("apiHost"),
+				config.String("apiToken"),
+				repository,
+				map[domain.IssueType]string{
+					domain.IssueTypeBug:     config.String("bugLabel"),
+					domain.IssueTypeStory:   config.String("storyLabel"),
+					domain.IssueTypeChore:   config.String("choreLabel"),
+					domain.IssueTypeRelease: config.String("releaseLabel"),
+				},
+				config.Duration("requestTimeout"),
+			), nil
+		},
+	})
+}
+
+type githubTrackerService struct {
+	githubClient *github.Client
+	repository   string
+	typeLabels   map[domain.IssueType]string
+}
+
+func newGitHubTrackerService(
+	apiHost string, apiToken string, repository string,
+	typeLabels map[domain.IssueType]string, requestTimeout time.Duration,
+) TrackerService {
+	httpClient := &http.Client{Timeout: requestTimeout}
+	return &githubTrackerService{
+		githubClient: github.NewGitHubClient(apiHost, apiToken, httpClient),
+		repository:   repository,
+		typeLabels:   typeLabels,
+	}
+}
+
+// ImportIssues creates the issues one by one. Epics of the form "#12" are
+// parent tracking issues, which the issues are added to as task list items,
+// and other epics are the titles of milestones, which are created when the
+// repository has none with the title. The issues which fail to be created
+// are reported and have no ID. Attachments and local images cannot be
+// uploaded to GitHub issues, so they are reported too.
+func (g *githubTrackerService) ImportIssues(
+	ctx context.Context, domainIssues []*domain.Issue,
+) error {
+	if g.repository == "" {
+		return errors.New("No repository to import the issues in")
+	}
+
+	milestones, err := g.findMilestones(ctx, domainIssues)
+	if err != nil {
+		return err
+	}
+
+	// the numbers of the issues tracked by each parent issue, in the order
+	// of the issues
+	parents := []int{}
+	trackedIssues := map[int][]int{}
+	for _, domainIssue := range domainIssues {
+		// map type and labels
+		labels := []string{}
+		if label := g.typeLabels[domainIssue.Type]; label != "" {
+			labels = append(labels, label)
+		}
+		for _, domainLabel := range domainIssue.Labels {
+			labels = append(labels, domainLabel.Label)
+		}
+
+		// map epic
+		milestone, parent := 0, 0
+		if domainIssue.Epic != nil {
+			matches := githubParentRe.FindStringSubmatch(domainIssue.Epic.ID)
+			if matches != nil {
+				parent, _ = strconv.Atoi(matches[1])
+			} else {
+				milestone = milestones[domainIssue.Epic.ID]
+			}
+		}
+
+		number, err := g.githubClient.CreateIssue(
+			ctx, g.repository, domainIssue.Title,
+			markdown.RenderGitHubDocument(domainIssue.Description),
+			labels, milestone,
+		)
+		if isCancelled(err) {
+			return err
+		} else if err != nil {
+			log.Printf("Failed to create issue '%s': %s",
+				domainIssue.Title,
+				err)
+			continue
+		}
+		domainIssue.ID = "#" + strconv.Itoa(number)
+
+		// GitHub issues have no attachments
+		for _, attachment := range domainIssue.Attachments {
+			log.Printf(
+				"Failed to attach file '%s' to issue '%s': GitHub issues "+
+					"cannot have attachments",
+				attachment.Path,
+				domainIssue.Title,
+			)
+		}
+		g.reportLocalImages(domainIssue)
+
+		if parent != 0 {
+			if _, ok := trackedIssues[parent]; !ok {
+				parents = append(parents, parent)
+			}
+			trackedIssues[parent] = append(trackedIssues[parent], number)
+		}
+	}
+
+	for _, parent := range parents {
+		err := g.trackIssues(ctx, parent, trackedIssues[parent])
+		if isCancelled(err) {
+			return err
+		} else if err != nil {
+			log.Printf("Failed to add issues to tracking issue #%d: %s",
+				parent,
+				err)
+		}
+	}
+
+	return nil
+}
+
+// reportLocalImages reports the local images of the description of the
+// issue, which are left as links to their paths.
+func (g *githubTrackerService) reportLocalImages(domainIssue *domain.Issue) {
+	if domainIssue.Description == nil {
+		return
+	}
+
+	// images are reported once, however many times they are embedded
+	reported := map[string]bool{}
+	for _, image := range domainIssue.Description.Images() {
+		if !image.IsLocal() || reported[image.Source] {
+			continue
+		}
+		reported[image.Source] = true
+		log.Printf(
+			"Failed to upload image '%s' of issue '%s': GitHub issues "+
+				"cannot have attachments",
+			image.Source,
+			domainIssue.Title,
+		)
+	}
+}
+
+// findMilestones returns the numbers of the milestones of the epics of the
+// issues which are not parent tracking issues, by their titles. Missing
+// milestones are created. Only the error of a cancelled request is returned.
+func (g *githubTrackerService) findMilestones(
+	ctx context.Context, domainIssues []*domain.Issue,
+) (map[string]int, error) {
+	milestones := map[string]int{}
+	titles := []string{}
+	for _, domainIssue := range domainIssues {
+		if domainIssue.Epic == nil ||
+			githubParentRe.MatchString(domainIssue.Epic.ID) {
+			continue
+		}
+		if _, ok := milestones[domainIssue.Epic.ID]; !ok {
+			milestones[domainIssue.Epic.ID] = 0
+			titles = append(titles, domainIssue.Epic.ID)
+		}
+	}
+	if len(titles) == 0 {
+		return milestones, nil
+	}
+
+	repoMilestones, err := g.githubClient.Milestones(ctx, g.repository)
+	if isCancelled(err) {
+		return nil, err
+	} else if err != nil {
+		log.Printf("Failed to find the milestones of repository '%s': %s",
+			g.repository,
+			err)
+		return milestones, nil
+	}
+	for _, milestone := range repoMilestones {
+		if _, ok := milestones[milestone.Title]; ok {
+			milestones[milestone.Title] = milestone.Number
+		}
+	}
+
+	for _, title := range titles {
+		if milestones[title] != 0 {
+			continue
+		}
+		milestone, err := g.githubClient.CreateMilestone(
+			ctx, g.repository, title,
+		)
+		if isCancelled(err) {
+			return nil, err
+		} else if err != nil {
+			log.Printf("Failed to create milestone '%s': %s", title, err)
+			continue
+		}
+		milestones[title] = milestone.Number
+	}
+	return milestones, nil
+}
+
+// trackIssues adds the issues to the task list of the parent issue, which
+// GitHub shows as the issues it tracks.
+func (g *githubTrackerService) trackIssues(
+	ctx context.Context, parent int, numbers []int,
+) error {
+	parentIssue, err := g.githubClient.GetIssue(ctx, g.repository, parent)
+	if err != nil {
+		return err
+	}
+
+	body := strings.TrimRight(parentIssue.Body, "\n")
+	if body != "" {
+		body += "\n\n"
+	}
+	for _, number := range numbers {
+		body += fmt.Sprintf("- [ ] #%d\n", number)
+	}
+	return g.githubClient.UpdateIssueBody(ctx, g.repository, parent, body)
+}
+
+// ExportIssues exports the issues matching the query of the GitHub search
+// syntax. The query is limited to the repository, unless it has a "repo:"
+// qualifier. Descriptions which fail to parse, such as the ones with HTML
+// blocks, are reported and kept as markdown code blocks.
+func (g *githubTrackerService) ExportIssues(
+	ctx context.Context, query string,
+) ([]*domain.Issue, error) {
+	if g.repository != "" && !strings.Contains(query, "repo:") {
+		query = "repo:" + g.repository + " " + query
+	}
+	githubIssues, err := g.githubClient.SearchIssues(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	labelTypes := map[string]domain.IssueType{}
+	for issueType, label := range g.typeLabels {
+		labelTypes[label] = issueType
+	}
+
+	domainIssues := make([]*domain.Issue, len(githubIssues))
+	for i, githubIssue := range githubIssues {
+		domainIssue := &domain.Issue{}
+
+		// map number
+		domainIssue.ID = "#" + strconv.Itoa(githubIssue.Number)
+
+		// map title
+		domainIssue.Title = githubIssue.Title
+
+		// map description
+		domainIssue.Description, err = markdown.ParseDocument(
+			[]byte(githubIssue.Body), markdown.ParserOptions{},
+		)
+		if err != nil {
+			log.Printf(
+				"Failed to parse description of issue '%s', keeping it "+
+					"as a code block: %s",
+				domainIssue.ID,
+				err,
+			)
+			domainIssue.Description = &domain.Document{}
+			domainIssue.Description.AddCodeBlock("markdown", githubIssue.Body)
+		}
+
+		// map milestone
+		if githubIssue.Milestone != nil {
+			domainIssue.Epic = &domain.Epic{ID: githubIssue.Milestone.Title}
+		}
+
+		// map type and labels
+		domainIssue.Type = domain.IssueTypeStory
+		for _, githubLabel := range githubIssue.Labels {
+			if issueType, ok := labelTypes[githubLabel.Name]; ok {
+				domainIssue.Type = issueType
+				continue
+			}
+			domainIssue.Labels = append(domainIssue.Labels, domain.Label{
+				Label: githubLabel.Name,
+			})
+		}
+
+		domainIssues[i] = domainIssue
+	}
+
+	return domainIssues, nil
+}
+
+func (g *githubTrackerService) TestConnection(ctx context.Context) error {
+	return g.githubClient.Test(ctx)
+}
@@ -0,0 +1,229 @@
+package tracker_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/tracker"
+	"github.com/stretchr/testify/require"
+)
+
+func newFakeGitHubTrackerService(
+	t *testing.T, handler http.HandlerFunc,
+) (tracker.TrackerService, func()) {
+	server := httptest.NewServer(handler)
+	trackerService, err := tracker.NewTrackerService(domain.Tracker{
+		Type: "github",
+		Config: map[string]string{
+			"apiHost":    server.URL + "/api/v3",
+			"apiToken":   "token",
+			"repository": "owner/repo",
+		},
+	})
+	require.NoError(t, err)
+
+	return trackerService, server.Close
+}
+
+func TestGitHubTrackerImportIssues(t *testing.T) {
+	createdIssues := []map[string]interface{}{}
+	createdMilestones := []string{}
+	var parentBody string
+	trackerService, closeServer := newFakeGitHubTrackerService(
+		t, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "token token", r.Header.Get("Authorization"))
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			switch r.Method + " " + r.URL.Path {
+			case "GET /api/v3/repos/owner/repo/milestones":
+				w.Write([]byte(`[{"number": 1, "title": "v1.0"}]`))
+			case "POST /api/v3/repos/owner/repo/milestones":
+				milestone := map[string]string{}
+				require.NoError(t, json.Unmarshal(body, &milestone))
+				createdMilestones = append(
+					createdMilestones, milestone["title"],
+				)
+				w.WriteHeader(201)
+				w.Write([]byte(`{"number": 2, "title": "v2.0"}`))
+			case "POST /api/v3/repos/owner/repo/issues":
+				issue := map[string]interface{}{}
+				require.NoError(t, json.Unmarshal(body, &issue))
+				createdIssues = append(createdIssues, issue)
+				w.WriteHeader(201)
+				fmt.Fprintf(w, `{"number": %d}`, 10+len(createdIssues))
+			case "GET /api/v3/repos/owner/repo/issues/7":
+				w.Write([]byte(`{"number": 7, "body": "Tracks:\n"}`))
+			case "PATCH /api/v3/repos/owner/repo/issues/7":
+				update := map[string]string{}
+				require.NoError(t, json.Unmarshal(body, &update))
+				parentBody = update["body"]
+				w.Write([]byte(`{"number": 7}`))
+			default:
+				t.Fatalf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+		},
+	)
+	defer closeServer()
+
+	description := &domain.Document{}
+	description.AddParagraph().AddText("It ", domain.TextMode{})
+	content := &description.Nodes[0].ParagraphData.Content
+	content.AddText("breaks", domain.TextMode{Bold: true})
+	content.AddText(" for ", domain.TextMode{})
+	content.AddMention("Alice Smith", "")
+	content.AddText(" on ", domain.TextMode{})
+	content.AddDate("2026-11-01")
+	description.AddImage("./shots/error.png", "error")
+	issues := []*domain.Issue{
+		{
+			Type:        domain.IssueTypeBug,
+			Title:       "A bug",
+			Description: description,
+			Epic:        &domain.Epic{ID: "v1.0"},
+			Labels:      []domain.Label{{Label: "backend"}},
+		},
+		{
+			Type:  domain.IssueTypeStory,
+			Title: "A story",
+			Epic:  &domain.Epic{ID: "v2.0"},
+		},
+		{
+			Type:  domain.IssueTypeChore,
+			Title: "A chore",
+			Epic:  &domain.Epic{ID: "#7"},
+		},
+		{
+			Type:  domain.IssueTypeChore,
+			Title: "Another chore",
+			Epic:  &domain.Epic{ID: "7"},
+		},
+	}
+	err := trackerService.ImportIssues(context.Background(), issues)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"v2.0"}, createdMilestones)
+	require.Equal(t, []map[string]interface{}{
+		{
+			"title": "A bug",
+			"body": "It **breaks** for Alice Smith on 2026-11-01\n\n" +
+				"![error](./shots/error.png)",
+			"labels":    []interface{}{"bug", "backend"},
+			"milestone": float64(1),
+		},
+		{
+			"title":     "A story",
+			"labels":    []interface{}{"enhancement"},
+			"milestone": float64(2),
+		},
+		{"title": "A chore", "labels": []interface{}{"chore"}},
+		{"title": "Another chore", "labels": []interface{}{"chore"}},
+	}, createdIssues)
+	require.Equal(t, "#11", issues[0].ID)
+	require.Equal(t, "#14", issues[3].ID)
+	require.Equal(t, "Tracks:\n\n- [ ] #13\n- [ ] #14\n", parentBody)
+}
+
+func TestGitHubTrackerImportIssuesFailure(t *testing.T) {
+	created := 0
+	trackerService, closeServer := newFakeGitHubTrackerService(
+		t, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/api/v3/repos/owner/repo/issues", r.URL.Path)
+			created++
+			if created == 1 {
+				w.WriteHeader(422)
+				return
+			}
+			w.WriteHeader(201)
+			fmt.Fprintf(w, `{"number": %d}`, created)
+		},
+	)
+	defer closeServer()
+
+	issues := []*domain.Issue{{Title: "Invalid"}, {Title: "Valid"}}
+	err := trackerService.ImportIssues(context.Background(), issues)
+	require.NoError(t, err)
+	require.Equal(t, "", issues[0].ID)
+	require.Equal(t, "#2", issues[1].ID)
+}
+
+func TestGitHubTrackerImportIssuesNoRepository(t *testing.T) {
+	trackerService, err := tracker.NewTrackerService(domain.Tracker{
+		Type:   "github",
+		Config: map[string]string{"apiToken": "token"},
+	})
+	require.NoError(t, err)
+
+	err = trackerService.ImportIssues(
+		context.Background(), []*domain.Issue{{Title: "A story"}},
+	)
+	require.EqualError(t, err, "No repository to import the issues in")
+}
+
+func TestGitHubTrackerInvalidRepository(t *testing.T) {
+	_, err := tracker.NewTrackerService(domain.Tracker{
+		Type: "github",
+		Config: map[string]string{
+			"apiToken":   "token",
+			"repository": "repo",
+		},
+	})
+	require.EqualError(
+		t, err, "Invalid repository 'repo', expected owner/name",
+	)
+}
+
+func TestGitHubTrackerExportIssues(t *testing.T) {
+	trackerService, closeServer := newFakeGitHubTrackerService(
+		t, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/api/v3/search/issues", r.URL.Path)
+			require.Equal(
+				t, "repo:owner/repo is:open", r.URL.Query().Get("q"),
+			)
+			w.Write([]byte(`{
+  "total_count": 3,
+  "items": [
+    {
+      "number": 3,
+      "title": "A bug",
+      "body": "It **breaks**",
+      "labels": [{"name": "bug"}, {"name": "backend"}],
+      "milestone": {"number": 1, "title": "v1.0"}
+    },
+    {"number": 4, "title": "A story", "body": null, "labels": []},
+    {"number": 5, "title": "A chore", "body": "Text\n\n<div>\nRaw\n</div>\n"}
+  ]
+}`))
+		},
+	)
+	defer closeServer()
+
+	issues, err := trackerService.ExportIssues(
+		context.Background(), "is:open",
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 3)
+
+	require.Equal(t, "#3", issues[0].ID)
+	require.Equal(t, domain.IssueTypeBug, issues[0].Type)
+	require.Equal(t, "A bug", issues[0].Title)
+	require.Equal(t, &domain.Epic{ID: "v1.0"}, issues[0].Epic)
+	require.Equal(t, []domain.Label{{Label: "backend"}}, issues[0].Labels)
+	require.Len(t, issues[0].Description.Nodes, 1)
+
+	require.Equal(t, "#4", issues[1].ID)
+	require.Equal(t, domain.IssueTypeStory, issues[1].Type)
+	require.Nil(t, issues[1].Description)
+	require.Nil(t, issues[1].Epic)
+
+	description := &domain.Document{}
+	description.AddCodeBlock("markdown", "Text\n\n<div>\nRaw\n</div>\n")
+	require.Equal(t, "#5", issues[2].ID)
+	require.Equal(t, description, issues[2].Description)
+}
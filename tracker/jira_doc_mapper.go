@@ -2,6 +2,9 @@ package tracker
 
 import (
 	"fmt"
+	"path/filepath"
+	"regexp"
+	"time"
 
 	"github.com/glestaris/issuez/domain"
 	"github.com/glestaris/issuez/jira"
@@ -12,22 +15,93 @@ func mapTextMode(tceMode domain.TextMode) (jtMode jira.ADFTextMode) {
 	jtMode.Code = tceMode.Code
 	jtMode.Em = tceMode.Italics
 	jtMode.Strike = tceMode.Strikethrough
+	jtMode.Underline = tceMode.Underline
+	jtMode.Sub = tceMode.Subscript
+	jtMode.Sup = tceMode.Superscript
+	jtMode.Color = tceMode.Color
 	return
 }
 
+// isInlineCard checks whether the element is a plain link to its own URL,
+// which is shown as a smart link.
+func isInlineCard(tce domain.TextElement) bool {
+	return tce.LinkURL != "" && tce.Text == tce.LinkURL &&
+		tce.Mode == (domain.TextMode{})
+}
+
+// linkIssueKeys returns the text with the issue keys matched by keyRe
+// turned into links to the issues. Plain keys become links to their own URL,
+// which are shown as smart links. Keys in code or links are not changed.
+func linkIssueKeys(
+	tc domain.TextContainer, keyRe *regexp.Regexp,
+	issueURL func(issueKey string) string,
+) domain.TextContainer {
+	linked := domain.TextContainer{}
+	for _, tce := range tc.Elements {
+		if tce.Type != domain.TextElementTypeText || tce.LinkURL != "" ||
+			tce.Mode.Code {
+			linked.Elements = append(linked.Elements, tce)
+			continue
+		}
+
+		start := 0
+		for _, loc := range keyRe.FindAllStringIndex(tce.Text, -1) {
+			if loc[0] > start {
+				linked.AddText(tce.Text[start:loc[0]], tce.Mode)
+			}
+			issueKey := tce.Text[loc[0]:loc[1]]
+			if tce.Mode == (domain.TextMode{}) {
+				linked.AddLink(
+					issueURL(issueKey), issueURL(issueKey), tce.Mode,
+				)
+			} else {
+				linked.AddLink(issueKey, issueURL(issueKey), tce.Mode)
+			}
+			start = loc[1]
+		}
+		if start < len(tce.Text) {
+			linked.AddText(tce.Text[start:], tce.Mode)
+		}
+	}
+	return linked
+}
+
 func mapTextContainer(jt jira.ADFNodeText, tc domain.TextContainer) {
 	for _, tce := range tc.Elements {
 		jtMode := mapTextMode(tce.Mode)
-		if tce.LinkURL != "" {
-			jt.AddLink(tce.Text, tce.LinkURL, jtMode)
-		} else {
-			jt.AddText(tce.Text, jtMode)
+		switch tce.Type {
+		case domain.TextElementTypeHardBreak:
+			jt.AddHardBreak()
+		case domain.TextElementTypeMention:
+			if tce.AccountID != "" {
+				jt.AddMention(tce.AccountID, "@"+tce.Text)
+			} else {
+				// users not found in JIRA are kept as text
+				jt.AddText("@"+tce.Text, jtMode)
+			}
+		case domain.TextElementTypeEmoji:
+			jt.AddEmoji(":" + tce.Text + ":")
+		case domain.TextElementTypeDate:
+			date, err := time.Parse("2006-01-02", tce.Text)
+			if err != nil {
+				jt.AddText(tce.Text, jtMode)
+			} else {
+				jt.AddDate(date)
+			}
+		default:
+			if isInlineCard(tce) {
+				jt.AddInlineCard(tce.LinkURL)
+			} else if tce.LinkURL != "" {
+				jt.AddLink(tce.Text, tce.LinkURL, jtMode)
+			} else {
+				jt.AddText(tce.Text, jtMode)
+			}
 		}
 	}
 }
 
-func addParagraph(jd jira.ADFDocument, node domain.DocumentNode) {
-	p := jd.AddParagraph()
+func addParagraph(jb jira.ADFNodeBlocks, node domain.DocumentNode) {
+	p := jb.AddParagraph()
 	mapTextContainer(p, node.Content)
 }
 
@@ -47,27 +121,278 @@ func mapHeadingLevel(hl domain.HeadingLevel) (jhl jira.ADFHeadingLevel) {
 	return
 }
 
-func addHeading(jd jira.ADFDocument, node domain.DocumentNode) {
+func addHeading(jd jira.ADFNodeExpand, node domain.DocumentNode) {
 	jd.AddHeading(
 		mapHeadingLevel(node.HeadingData.Level), node.HeadingData.Text,
 	)
 }
 
-func addList(jd jira.ADFDocument, node domain.DocumentNode) {
+// addList adds a list. Task lists are added as bullet lists whose items start
+// with their checkbox.
+func addList(jb jira.ADFNodeBlocks, node domain.DocumentNode) error {
 	var l jira.ADFNodeList
 	if node.ListData.IsOrdered {
-		l = jd.AddOrderedList()
+		l = jb.AddOrderedList()
 	} else {
-		l = jd.AddOrderedList()
+		l = jb.AddBulletList()
 	}
-	for _, tc := range node.ListData.Items {
-		jt := l.AddItem()
-		mapTextContainer(jt, tc)
+	for _, item := range node.ListData.Items {
+		jli := l.AddItem()
+		if node.ListData.IsTaskList && item.IsChecked {
+			jli.AddText("[x] ", jira.ADFTextMode{})
+		} else if node.ListData.IsTaskList {
+			jli.AddText("[ ] ", jira.ADFTextMode{})
+		}
+		mapTextContainer(jli, item.TextContainer)
+		for _, itemNode := range item.Nodes {
+			if err := addBlock(jli, itemNode); err != nil {
+				return err
+			}
+		}
 	}
+	return nil
+}
+
+func addCodeBlock(jb jira.ADFNodeBlocks, node domain.DocumentNode) {
+	jb.AddCodeBlock(node.CodeBlockData.Language, node.CodeBlockData.Code)
 }
 
-func addCodeBlock(jd jira.ADFDocument, node domain.DocumentNode) {
-	jd.AddCodeBlock(node.CodeBlockData.Language, node.CodeBlockData.Code)
+// addBlock adds a block to a node which cannot contain headings, quotes,
+// panels, tables, expands or rules (e.g. a list item). Headings and the
+// titles of expands are added as bold paragraphs, the blocks of quotes,
+// panels and expands are added in their place, the rows of tables are added
+// as paragraphs and rules are dropped.
+func addBlock(jb jira.ADFNodeBlocks, node domain.DocumentNode) error {
+	switch node.Type {
+	case domain.DocumentNodeTypeParagraph:
+		addParagraph(jb, node)
+	case domain.DocumentNodeTypeList:
+		return addList(jb, node)
+	case domain.DocumentNodeTypeHeading:
+		p := jb.AddParagraph()
+		p.AddText(node.HeadingData.Text, jira.ADFTextMode{Strong: true})
+	case domain.DocumentNodeTypeCodeBlock:
+		addCodeBlock(jb, node)
+	case domain.DocumentNodeTypeBlockQuote:
+		return addBlocks(jb, node.BlockQuoteData.Nodes)
+	case domain.DocumentNodeTypePanel:
+		return addBlocks(jb, node.PanelData.Nodes)
+	case domain.DocumentNodeTypeExpand:
+		addExpandTitle(jb, node)
+		return addBlocks(jb, node.ExpandData.Nodes)
+	case domain.DocumentNodeTypeTable:
+		addTableRows(jb, node)
+	case domain.DocumentNodeTypeImage:
+		addImage(jb, node)
+	case domain.DocumentNodeTypeRule:
+		// rules cannot be nested
+	default:
+		return fmt.Errorf("Cannot map document node type %s to Jira",
+			node.Type)
+	}
+	return nil
+}
+
+// addImage adds a remote image as external media. Local images are added as
+// the media of the attachments they are uploaded as. An uploaded image whose
+// media is not known is added as a link to its attachment, and an image
+// which is not uploaded as its alt text, or its path.
+func addImage(jb jira.ADFNodeBlocks, node domain.DocumentNode) {
+	image := node.ImageData
+	if !image.IsLocal() {
+		jb.AddExternalImage(image.Source, image.AltText)
+		return
+	}
+
+	if image.MediaID != "" {
+		jb.AddFileImage(image.MediaID, image.AltText)
+		return
+	}
+
+	if image.AttachmentURL != "" {
+		text := image.AltText
+		if text == "" {
+			text = filepath.Base(image.Source)
+		}
+		jb.AddParagraph().AddLink(
+			text, image.AttachmentURL, jira.ADFTextMode{},
+		)
+		return
+	}
+
+	text := image.AltText
+	if text == "" {
+		text = image.Source
+	}
+	jb.AddParagraph().AddText(text, jira.ADFTextMode{Em: true})
+}
+
+func addBlocks(jb jira.ADFNodeBlocks, nodes []domain.DocumentNode) error {
+	for _, node := range nodes {
+		if err := addBlock(jb, node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mapPanelType(pt domain.PanelType) (jpt jira.ADFPanelType) {
+	switch pt {
+	case domain.PanelTypeInfo:
+		jpt = jira.ADFPanelTypeInfo
+	case domain.PanelTypeNote:
+		jpt = jira.ADFPanelTypeNote
+	case domain.PanelTypeSuccess:
+		jpt = jira.ADFPanelTypeSuccess
+	case domain.PanelTypeWarning:
+		jpt = jira.ADFPanelTypeWarning
+	case domain.PanelTypeError:
+		jpt = jira.ADFPanelTypeError
+	}
+	return
+}
+
+func addPanel(jd jira.ADFNodeExpand, node domain.DocumentNode) error {
+	jp := jd.AddPanel(mapPanelType(node.PanelData.PanelType))
+	for _, panelNode := range node.PanelData.Nodes {
+		if panelNode.Type == domain.DocumentNodeTypeHeading {
+			jp.AddHeading(
+				mapHeadingLevel(panelNode.HeadingData.Level),
+				panelNode.HeadingData.Text,
+			)
+			continue
+		}
+		if err := addBlock(jp, panelNode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mapAlignment(ta domain.TableAlignment) (ja jira.ADFAlignment) {
+	switch ta {
+	case domain.TableAlignmentLeft:
+		ja = jira.ADFAlignmentStart
+	case domain.TableAlignmentCenter:
+		ja = jira.ADFAlignmentCenter
+	case domain.TableAlignmentRight:
+		ja = jira.ADFAlignmentEnd
+	}
+	return
+}
+
+func addTable(jd jira.ADFNodeExpand, node domain.DocumentNode) {
+	jt := jd.AddTable()
+	for _, row := range node.TableData.Rows {
+		jr := jt.AddRow()
+		for i, cell := range row.Cells {
+			alignment := mapAlignment(node.TableData.Alignment(i))
+			var jc jira.ADFNodeText
+			if row.IsHeader {
+				jc = jr.AddHeaderCell(alignment)
+			} else {
+				jc = jr.AddCell(alignment)
+			}
+			mapTextContainer(jc, cell)
+		}
+	}
+}
+
+// addTableRows adds each row of the table as a paragraph, with its cells
+// separated by pipes.
+func addTableRows(jb jira.ADFNodeBlocks, node domain.DocumentNode) {
+	for _, row := range node.TableData.Rows {
+		p := jb.AddParagraph()
+		for i, cell := range row.Cells {
+			if i > 0 {
+				p.AddText(" | ", jira.ADFTextMode{})
+			}
+			mapTextContainer(p, cell)
+		}
+	}
+}
+
+// isTaskListMappable checks whether the blocks nested in the items of a task
+// list are task lists only, which are the only blocks Jira task lists can
+// contain.
+func isTaskListMappable(list *domain.ListData) bool {
+	for _, item := range list.Items {
+		for _, itemNode := range item.Nodes {
+			if itemNode.Type != domain.DocumentNodeTypeList ||
+				!itemNode.ListData.IsTaskList ||
+				!isTaskListMappable(itemNode.ListData) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func addTaskList(jl jira.ADFNodeTaskList, list *domain.ListData) {
+	for _, item := range list.Items {
+		mapTextContainer(jl.AddItem(item.IsChecked), item.TextContainer)
+		for _, itemNode := range item.Nodes {
+			addTaskList(jl.AddTaskList(), itemNode.ListData)
+		}
+	}
+}
+
+// addExpandTitle adds the title of an expand which cannot be nested as a
+// bold paragraph.
+func addExpandTitle(jb jira.ADFNodeBlocks, node domain.DocumentNode) {
+	if node.ExpandData.Title != "" {
+		p := jb.AddParagraph()
+		p.AddText(node.ExpandData.Title, jira.ADFTextMode{Strong: true})
+	}
+}
+
+// addExpand adds an expand to the document. Expands cannot be empty, so an
+// expand without blocks gets an empty paragraph.
+func addExpand(jd jira.ADFDocument, node domain.DocumentNode) error {
+	je := jd.AddExpand(node.ExpandData.Title)
+	if len(node.ExpandData.Nodes) == 0 {
+		je.AddParagraph()
+	}
+	for _, expandNode := range node.ExpandData.Nodes {
+		if err := addExpandBlock(je, expandNode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addExpandBlock adds a block to the document or to an expand. Expands
+// cannot be nested, so the blocks of a nested expand are added in its place,
+// after its title.
+func addExpandBlock(je jira.ADFNodeExpand, node domain.DocumentNode) error {
+	switch node.Type {
+	case domain.DocumentNodeTypeHeading:
+		addHeading(je, node)
+	case domain.DocumentNodeTypeBlockQuote:
+		return addBlocks(je.AddBlockquote(), node.BlockQuoteData.Nodes)
+	case domain.DocumentNodeTypePanel:
+		return addPanel(je, node)
+	case domain.DocumentNodeTypeTable:
+		addTable(je, node)
+	case domain.DocumentNodeTypeRule:
+		je.AddRule()
+	case domain.DocumentNodeTypeExpand:
+		addExpandTitle(je, node)
+		for _, expandNode := range node.ExpandData.Nodes {
+			if err := addExpandBlock(je, expandNode); err != nil {
+				return err
+			}
+		}
+	case domain.DocumentNodeTypeList:
+		if node.ListData.IsTaskList && isTaskListMappable(node.ListData) {
+			addTaskList(je.AddTaskList(), node.ListData)
+		} else {
+			return addList(je, node)
+		}
+	default:
+		return addBlock(je, node)
+	}
+	return nil
 }
 
 func mapDocument(domainDoc *domain.Document) (jira.ADFDocument, error) {
@@ -77,18 +402,14 @@ func mapDocument(domainDoc *domain.Document) (jira.ADFDocument, error) {
 
 	jd := jira.NewADFDocument()
 	for _, node := range domainDoc.Nodes {
-		switch node.Type {
-		case domain.DocumentNodeTypeParagraph:
-			addParagraph(jd, node)
-		case domain.DocumentNodeTypeList:
-			addList(jd, node)
-		case domain.DocumentNodeTypeHeading:
-			addHeading(jd, node)
-		case domain.DocumentNodeTypeCodeBlock:
-			addCodeBlock(jd, node)
-		default:
-			return nil, fmt.Errorf("Cannot map document node type %s to Jira",
-				node.Type)
+		var err error
+		if node.Type == domain.DocumentNodeTypeExpand {
+			err = addExpand(jd, node)
+		} else {
+			err = addExpandBlock(jd, node)
+		}
+		if err != nil {
+			return nil, err
 		}
 	}
 
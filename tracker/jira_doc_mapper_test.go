@@ -0,0 +1,414 @@
+package tracker
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"regexp"
+	"testing"
+
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/jira"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapDocumentRoundTrip(t *testing.T) {
+	domainDoc := &domain.Document{}
+	domainDoc.AddHeading(domain.HeadingLevel3, "Title")
+	p := domainDoc.AddParagraph()
+	p.AddText("Hello ", domain.TextMode{})
+	p.AddText("world", domain.TextMode{Bold: true, Strikethrough: true})
+	p.AddLink("link", "https://google.com", domain.TextMode{Italics: true})
+	ol := domainDoc.AddOrderedList()
+	ol.AddItem().AddText("One", domain.TextMode{})
+	ol.AddItem().AddText("Two", domain.TextMode{Code: true})
+	ul := domainDoc.AddUnorderedList()
+	ul.AddItem().AddText("Bullet", domain.TextMode{})
+	item := ul.AddItem()
+	item.AddText("Nested", domain.TextMode{})
+	nestedItem := item.AddOrderedList().AddItem()
+	nestedItem.AddText("Deeper", domain.TextMode{})
+	nestedItem.AddUnorderedList().AddItem().AddText("Deepest", domain.TextMode{})
+	item.AddParagraph().AddText("More", domain.TextMode{})
+	item.AddCodeBlock("go", "x := 12\n")
+	domainDoc.AddCodeBlock("python", "x = 12\n")
+	domainDoc.AddRule()
+	quote := domainDoc.AddBlockQuote()
+	quote.AddParagraph().AddText("Quoted", domain.TextMode{})
+	quote.AddUnorderedList().AddItem().AddText("In quote", domain.TextMode{})
+	panel := domainDoc.AddPanel(domain.PanelTypeError)
+	panel.AddHeading(domain.HeadingLevel1, "Broken")
+	panel.AddCodeBlock("", "panic\n")
+	table := domainDoc.AddTable(
+		domain.TableAlignmentLeft, domain.TableAlignmentRight,
+	)
+	header := table.AddHeaderRow()
+	header.AddCell().AddText("Name", domain.TextMode{})
+	header.AddCell().AddText("Count", domain.TextMode{})
+	row := table.AddRow()
+	row.AddCell().AddText("Errors", domain.TextMode{Italics: true})
+	row.AddCell().AddText("12", domain.TextMode{Code: true})
+
+	jd, err := mapDocument(domainDoc)
+	require.NoError(t, err)
+	jdJSON, err := json.Marshal(jd)
+	require.NoError(t, err)
+
+	parsedJD, err := jira.ParseADF(jdJSON)
+	require.NoError(t, err)
+	readDomainDoc, err := readDocument(parsedJD)
+	require.NoError(t, err)
+	require.Equal(t, domainDoc, readDomainDoc)
+}
+
+func TestReadDocumentEmpty(t *testing.T) {
+	domainDoc, err := readDocument(nil)
+	require.NoError(t, err)
+	require.Nil(t, domainDoc)
+
+	domainDoc, err = readDocument(&jira.ADFNode{Type: "doc"})
+	require.NoError(t, err)
+	require.Nil(t, domainDoc)
+
+	_, err = readDocument(&jira.ADFNode{Type: "paragraph"})
+	require.Error(t, err)
+}
+
+func TestMapDocumentListItemHeading(t *testing.T) {
+	domainDoc := &domain.Document{}
+	item := domainDoc.AddUnorderedList().AddItem()
+	item.AddText("Item", domain.TextMode{})
+	item.AddHeading(domain.HeadingLevel2, "Heading")
+
+	jd, err := mapDocument(domainDoc)
+	require.NoError(t, err)
+	jdJSON, err := json.Marshal(jd)
+	require.NoError(t, err)
+
+	parsedJD, err := jira.ParseADF(jdJSON)
+	require.NoError(t, err)
+	readDomainDoc, err := readDocument(parsedJD)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	expectedItem := expected.AddUnorderedList().AddItem()
+	expectedItem.AddText("Item", domain.TextMode{})
+	expectedItem.AddParagraph().AddText("Heading", domain.TextMode{Bold: true})
+	require.Equal(t, expected, readDomainDoc)
+}
+
+func TestMapDocumentNestedQuotes(t *testing.T) {
+	domainDoc := &domain.Document{}
+	item := domainDoc.AddUnorderedList().AddItem()
+	item.AddText("Item", domain.TextMode{})
+	item.AddBlockQuote().AddParagraph().AddText("Quoted", domain.TextMode{})
+	panel := domainDoc.AddPanel(domain.PanelTypeNote)
+	panel.AddBlockQuote().AddParagraph().AddText("In panel", domain.TextMode{})
+
+	jd, err := mapDocument(domainDoc)
+	require.NoError(t, err)
+	jdJSON, err := json.Marshal(jd)
+	require.NoError(t, err)
+
+	parsedJD, err := jira.ParseADF(jdJSON)
+	require.NoError(t, err)
+	readDomainDoc, err := readDocument(parsedJD)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	expectedItem := expected.AddUnorderedList().AddItem()
+	expectedItem.AddText("Item", domain.TextMode{})
+	expectedItem.AddParagraph().AddText("Quoted", domain.TextMode{})
+	expected.AddPanel(domain.PanelTypeNote).AddParagraph().AddText(
+		"In panel", domain.TextMode{},
+	)
+	require.Equal(t, expected, readDomainDoc)
+}
+
+func TestMapDocumentNestedTable(t *testing.T) {
+	domainDoc := &domain.Document{}
+	item := domainDoc.AddUnorderedList().AddItem()
+	item.AddText("Item", domain.TextMode{})
+	row := item.AddTable().AddRow()
+	row.AddCell().AddText("a", domain.TextMode{})
+	row.AddCell().AddText("b", domain.TextMode{Bold: true})
+
+	jd, err := mapDocument(domainDoc)
+	require.NoError(t, err)
+	jdJSON, err := json.Marshal(jd)
+	require.NoError(t, err)
+
+	parsedJD, err := jira.ParseADF(jdJSON)
+	require.NoError(t, err)
+	readDomainDoc, err := readDocument(parsedJD)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	expectedItem := expected.AddUnorderedList().AddItem()
+	expectedItem.AddText("Item", domain.TextMode{})
+	p := expectedItem.AddParagraph()
+	p.AddText("a | ", domain.TextMode{})
+	p.AddText("b", domain.TextMode{Bold: true})
+	require.Equal(t, expected, readDomainDoc)
+}
+
+func TestMapDocumentExpands(t *testing.T) {
+	domainDoc := &domain.Document{}
+	expand := domainDoc.AddExpand("Stack trace")
+	expand.AddCodeBlock("", "panic: oops\n")
+	expand.AddRule()
+	nested := expand.AddExpand("Logs")
+	nested.AddParagraph().AddText("Nested", domain.TextMode{})
+	domainDoc.AddExpand("Empty")
+	item := domainDoc.AddUnorderedList().AddItem()
+	item.AddText("Item", domain.TextMode{})
+	item.AddExpand("Details").AddParagraph().AddText(
+		"In item", domain.TextMode{},
+	)
+	item.AddRule()
+
+	jd, err := mapDocument(domainDoc)
+	require.NoError(t, err)
+	jdJSON, err := json.Marshal(jd)
+	require.NoError(t, err)
+
+	parsedJD, err := jira.ParseADF(jdJSON)
+	require.NoError(t, err)
+	readDomainDoc, err := readDocument(parsedJD)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	expectedExpand := expected.AddExpand("Stack trace")
+	expectedExpand.AddCodeBlock("", "panic: oops\n")
+	expectedExpand.AddRule()
+	expectedExpand.AddParagraph().AddText("Logs", domain.TextMode{Bold: true})
+	expectedExpand.AddParagraph().AddText("Nested", domain.TextMode{})
+	expected.AddExpand("Empty").AddParagraph()
+	expectedItem := expected.AddUnorderedList().AddItem()
+	expectedItem.AddText("Item", domain.TextMode{})
+	expectedItem.AddParagraph().AddText("Details", domain.TextMode{Bold: true})
+	expectedItem.AddParagraph().AddText("In item", domain.TextMode{})
+	require.Equal(t, expected, readDomainDoc)
+}
+
+func TestMapDocumentImages(t *testing.T) {
+	domainDoc := &domain.Document{}
+	domainDoc.AddImage("https://e.com/bug.png", "Screenshot")
+	domainDoc.AddBlockQuote().AddImage("./bug.png", "Local")
+	domainDoc.AddImage("./bug.png", "")
+	domainDoc.AddImage("./shots/error.png", "")
+	domainDoc.Nodes[3].ImageData.AttachmentURL = "https://e.com/content/10"
+	domainDoc.AddImage("./shots/login.png", "Login")
+	domainDoc.Nodes[4].ImageData.AttachmentURL = "https://e.com/content/11"
+	domainDoc.Nodes[4].ImageData.MediaID = "0a1b2c3d-1111"
+
+	jd, err := mapDocument(domainDoc)
+	require.NoError(t, err)
+	jdJSON, err := json.Marshal(jd)
+	require.NoError(t, err)
+	require.Contains(
+		t, string(jdJSON),
+		`{"type":"mediaSingle","attrs":{"layout":"center"},"content":[`+
+			`{"type":"media","attrs":{"alt":"Login","collection":"",`+
+			`"id":"0a1b2c3d-1111","type":"file"}}]}`,
+	)
+
+	parsedJD, err := jira.ParseADF(jdJSON)
+	require.NoError(t, err)
+	readDomainDoc, err := readDocument(parsedJD)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	expected.AddImage("https://e.com/bug.png", "Screenshot")
+	expected.AddBlockQuote().AddParagraph().AddText(
+		"Local", domain.TextMode{Italics: true},
+	)
+	expected.AddParagraph().AddText(
+		"./bug.png", domain.TextMode{Italics: true},
+	)
+	expected.AddParagraph().AddLink(
+		"error.png", "https://e.com/content/10", domain.TextMode{},
+	)
+	// the files of the media store are not read back
+	require.Equal(t, expected, readDomainDoc)
+}
+
+func TestMapDocumentTaskLists(t *testing.T) {
+	domainDoc := &domain.Document{}
+	tasks := domainDoc.AddTaskList()
+	item := tasks.AddItem()
+	item.IsChecked = true
+	item.AddText("Done", domain.TextMode{})
+	item.AddTaskList().AddItem().AddText("Nested", domain.TextMode{})
+	// task lists cannot be quoted or contain other blocks
+	tasks = domainDoc.AddBlockQuote().AddTaskList()
+	tasks.AddItem().AddText("Quoted", domain.TextMode{})
+	tasks = domainDoc.AddTaskList()
+	tasks.AddItem().AddParagraph().AddText("Paragraph", domain.TextMode{})
+
+	jd, err := mapDocument(domainDoc)
+	require.NoError(t, err)
+	jdJSON, err := json.Marshal(jd)
+	require.NoError(t, err)
+
+	parsedJD, err := jira.ParseADF(jdJSON)
+	require.NoError(t, err)
+	readDomainDoc, err := readDocument(parsedJD)
+	require.NoError(t, err)
+
+	expected := &domain.Document{}
+	tasks = expected.AddTaskList()
+	item = tasks.AddItem()
+	item.IsChecked = true
+	item.AddText("Done", domain.TextMode{})
+	item.AddTaskList().AddItem().AddText("Nested", domain.TextMode{})
+	expected.AddBlockQuote().AddUnorderedList().AddItem().AddText(
+		"[ ] Quoted", domain.TextMode{},
+	)
+	item = expected.AddUnorderedList().AddItem()
+	item.AddText("[ ] ", domain.TextMode{})
+	item.AddParagraph().AddText("Paragraph", domain.TextMode{})
+	require.Equal(t, expected, readDomainDoc)
+}
+
+func TestMapDocumentMarks(t *testing.T) {
+	domainDoc := &domain.Document{}
+	p := domainDoc.AddParagraph()
+	p.AddText("Underline", domain.TextMode{Underline: true})
+	p.AddHardBreak()
+	p.AddText("Sub", domain.TextMode{Subscript: true})
+	p.AddText("Sup", domain.TextMode{Superscript: true, Color: "#ff5630"})
+	p.AddHardBreak()
+	p.AddLink("Link", "https://google.com", domain.TextMode{Underline: true})
+
+	jd, err := mapDocument(domainDoc)
+	require.NoError(t, err)
+	jdJSON, err := json.Marshal(jd)
+	require.NoError(t, err)
+
+	parsedJD, err := jira.ParseADF(jdJSON)
+	require.NoError(t, err)
+	readDomainDoc, err := readDocument(parsedJD)
+	require.NoError(t, err)
+	require.Equal(t, domainDoc, readDomainDoc)
+}
+
+func TestReadDocumentMarks(t *testing.T) {
+	data, err := ioutil.ReadFile("../assets/adf_all_nodes.json")
+	require.NoError(t, err)
+	jd, err := jira.ParseADF(data)
+	require.NoError(t, err)
+	domainDoc, err := readDocument(jd)
+	require.NoError(t, err)
+
+	elements := domainDoc.Nodes[1].Content.Elements
+	require.Equal(t, domain.TextElement{
+		Text: "Underline",
+		Mode: domain.TextMode{Underline: true},
+	}, elements[4])
+	require.Equal(t, domain.TextElement{
+		Text: "Sub",
+		Mode: domain.TextMode{Subscript: true},
+	}, elements[6])
+	require.Equal(t, domain.TextElement{
+		Text: "Red",
+		Mode: domain.TextMode{Color: "#ff0000"},
+	}, elements[7])
+	require.Equal(t, domain.TextElement{
+		Type: domain.TextElementTypeHardBreak,
+		Text: "\n",
+	}, elements[9])
+	require.Equal(t, domain.TextElement{
+		Type:      domain.TextElementTypeMention,
+		Text:      "Alice",
+		AccountID: "5b10ac8d82e05b22cc7d4ef5",
+	}, elements[10])
+	require.Equal(t, domain.TextElement{
+		Type: domain.TextElementTypeEmoji,
+		Text: "grinning",
+	}, elements[11])
+}
+
+func TestMapDocumentInlineNodes(t *testing.T) {
+	domainDoc := &domain.Document{}
+	p := domainDoc.AddParagraph()
+	p.AddText("Ask ", domain.TextMode{})
+	p.AddMention("Alice Smith", "5b10ac8d82e05b22cc7d4ef5")
+	p.AddText(" by ", domain.TextMode{})
+	p.AddDate("2026-11-01")
+	p.AddEmoji("warning")
+
+	jd, err := mapDocument(domainDoc)
+	require.NoError(t, err)
+	jdJSON, err := json.Marshal(jd)
+	require.NoError(t, err)
+
+	parsedJD, err := jira.ParseADF(jdJSON)
+	require.NoError(t, err)
+	readDomainDoc, err := readDocument(parsedJD)
+	require.NoError(t, err)
+	require.Equal(t, domainDoc, readDomainDoc)
+}
+
+func TestMapDocumentInlineCards(t *testing.T) {
+	domainDoc := &domain.Document{}
+	p := domainDoc.AddParagraph()
+	p.AddLink(
+		"https://example.com/a", "https://example.com/a", domain.TextMode{},
+	)
+	p.AddText(" ", domain.TextMode{})
+	p.AddLink(
+		"https://example.com/b", "https://example.com/b",
+		domain.TextMode{Bold: true},
+	)
+
+	jd, err := mapDocument(domainDoc)
+	require.NoError(t, err)
+	jdJSON, err := json.Marshal(jd)
+	require.NoError(t, err)
+	require.Contains(
+		t, string(jdJSON),
+		`{"type":"inlineCard","attrs":{"url":"https://example.com/a"}}`,
+	)
+	require.Contains(
+		t, string(jdJSON), `"text":"https://example.com/b","marks":[`,
+	)
+
+	parsedJD, err := jira.ParseADF(jdJSON)
+	require.NoError(t, err)
+	readDomainDoc, err := readDocument(parsedJD)
+	require.NoError(t, err)
+	require.Equal(t, domainDoc, readDomainDoc)
+}
+
+func TestLinkIssueKeys(t *testing.T) {
+	tc := domain.TextContainer{}
+	tc.AddText("See TEST-1, TEST-22 and OTHER-3 or ", domain.TextMode{})
+	tc.AddText("TEST-4", domain.TextMode{Code: true})
+	tc.AddText(" and ", domain.TextMode{})
+	tc.AddText("TEST-5", domain.TextMode{Bold: true})
+	tc.AddLink("TEST-6", "https://example.com", domain.TextMode{})
+
+	keyRe := regexp.MustCompile(`\bTEST-\d+\b`)
+	issueURL := func(issueKey string) string {
+		return "https://e.com/browse/" + issueKey
+	}
+	expected := domain.TextContainer{}
+	expected.AddText("See ", domain.TextMode{})
+	expected.AddLink(
+		"https://e.com/browse/TEST-1", "https://e.com/browse/TEST-1",
+		domain.TextMode{},
+	)
+	expected.AddText(", ", domain.TextMode{})
+	expected.AddLink(
+		"https://e.com/browse/TEST-22", "https://e.com/browse/TEST-22",
+		domain.TextMode{},
+	)
+	expected.AddText(" and OTHER-3 or ", domain.TextMode{})
+	expected.AddText("TEST-4", domain.TextMode{Code: true})
+	expected.AddText(" and ", domain.TextMode{})
+	expected.AddLink(
+		"TEST-5", "https://e.com/browse/TEST-5", domain.TextMode{Bold: true},
+	)
+	expected.AddLink("TEST-6", "https://example.com", domain.TextMode{})
+	require.Equal(t, expected, linkIssueKeys(tc, keyRe, issueURL))
+}
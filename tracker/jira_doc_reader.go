@@ -0,0 +1,274 @@
+package tracker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/jira"
+)
+
+func readTextMode(jn *jira.ADFNode) (mode domain.TextMode, linkURL string) {
+	for _, mark := range jn.Marks {
+		switch mark.Type {
+		case "strong":
+			mode.Bold = true
+		case "em":
+			mode.Italics = true
+		case "strike":
+			mode.Strikethrough = true
+		case "code":
+			mode.Code = true
+		case "underline":
+			mode.Underline = true
+		case "subsup":
+			switch mark.AttrString("type") {
+			case "sub":
+				mode.Subscript = true
+			case "sup":
+				mode.Superscript = true
+			}
+		case "textColor":
+			mode.Color = strings.ToLower(mark.AttrString("color"))
+		case "link":
+			linkURL = mark.AttrString("href")
+		}
+	}
+	return
+}
+
+func readTextContainer(tc *domain.TextContainer, jns []*jira.ADFNode) {
+	for _, jn := range jns {
+		switch jn.Type {
+		case "text":
+			mode, linkURL := readTextMode(jn)
+			if linkURL != "" {
+				tc.AddLink(jn.Text, linkURL, mode)
+			} else {
+				tc.AddText(jn.Text, mode)
+			}
+		case "hardBreak":
+			tc.AddHardBreak()
+		case "mention":
+			tc.AddMention(
+				strings.TrimPrefix(jn.AttrString("text"), "@"),
+				jn.AttrString("id"),
+			)
+		case "emoji":
+			tc.AddEmoji(strings.Trim(jn.AttrString("shortName"), ":"))
+		case "date":
+			if date, ok := readDate(jn); ok {
+				tc.AddDate(date)
+			}
+		case "inlineCard":
+			url := jn.AttrString("url")
+			tc.AddLink(url, url, domain.TextMode{})
+		default:
+			if text := jn.PlainText(); text != "" {
+				tc.AddText(text, domain.TextMode{})
+			}
+		}
+	}
+}
+
+// readDate returns the date of a date node in ISO format. The timestamp of
+// the node is in milliseconds.
+func readDate(jn *jira.ADFNode) (string, bool) {
+	timestamp, err := strconv.ParseInt(jn.AttrString("timestamp"), 10, 64)
+	if err != nil {
+		return "", false
+	}
+	date := time.Unix(0, timestamp*int64(time.Millisecond)).UTC()
+	return date.Format("2006-01-02"), true
+}
+
+func readHeadingLevel(jn *jira.ADFNode) domain.HeadingLevel {
+	level, _ := jn.AttrInt("level")
+	switch level {
+	case 1:
+		return domain.HeadingLevel1
+	case 2:
+		return domain.HeadingLevel2
+	case 3:
+		return domain.HeadingLevel3
+	case 4:
+		return domain.HeadingLevel4
+	default:
+		return domain.HeadingLevel5
+	}
+}
+
+func readPanelType(jn *jira.ADFNode) domain.PanelType {
+	switch jn.AttrString("panelType") {
+	case "note":
+		return domain.PanelTypeNote
+	case "success":
+		return domain.PanelTypeSuccess
+	case "warning":
+		return domain.PanelTypeWarning
+	case "error":
+		return domain.PanelTypeError
+	default:
+		return domain.PanelTypeInfo
+	}
+}
+
+// readList reads the items of a list. The first paragraph of an item is its
+// text and the blocks that follow are nested in it.
+func readList(list *domain.ListData, jn *jira.ADFNode) {
+	for _, listItem := range jn.Content {
+		item := list.AddItem()
+		blocks := listItem.Content
+		if len(blocks) != 0 && blocks[0].Type == "paragraph" {
+			readTextContainer(&item.TextContainer, blocks[0].Content)
+			blocks = blocks[1:]
+		}
+		readBlocks(&item.Document, blocks)
+	}
+}
+
+// readTaskList reads the items of a task list. Task lists nested in a task
+// list are nested in the item before them.
+func readTaskList(list *domain.ListData, jn *jira.ADFNode) {
+	for _, child := range jn.Content {
+		switch child.Type {
+		case "taskItem":
+			item := list.AddItem()
+			item.IsChecked = child.AttrString("state") == "DONE"
+			readTextContainer(&item.TextContainer, child.Content)
+		case "taskList":
+			if len(list.Items) == 0 {
+				list.AddItem()
+			}
+			item := &list.Items[len(list.Items)-1]
+			readTaskList(item.AddTaskList(), child)
+		}
+	}
+}
+
+func readAlignment(jn *jira.ADFNode) domain.TableAlignment {
+	for _, cellBlock := range jn.Content {
+		if mark := cellBlock.Mark("alignment"); mark != nil {
+			switch mark.AttrString("align") {
+			case "center":
+				return domain.TableAlignmentCenter
+			case "end":
+				return domain.TableAlignmentRight
+			}
+		}
+	}
+	return domain.TableAlignmentLeft
+}
+
+// readTableCell reads the blocks of a table cell as lines of text.
+func readTableCell(tc *domain.TextContainer, jn *jira.ADFNode) {
+	for i, cellBlock := range jn.Content {
+		if i > 0 {
+			tc.AddText("\n", domain.TextMode{})
+		}
+		if cellBlock.Type == "paragraph" {
+			readTextContainer(tc, cellBlock.Content)
+		} else if text := cellBlock.PlainText(); text != "" {
+			tc.AddText(text, domain.TextMode{})
+		}
+	}
+}
+
+// readTable reads the rows of a table. Rows made only of header cells are
+// header rows and the alignment of the columns is the alignment of the
+// cells of the first row.
+func readTable(domainDoc *domain.Document, jn *jira.ADFNode) {
+	var alignments []domain.TableAlignment
+	if len(jn.Content) != 0 {
+		for _, cell := range jn.Content[0].Content {
+			alignments = append(alignments, readAlignment(cell))
+		}
+	}
+
+	table := domainDoc.AddTable(alignments...)
+	for _, row := range jn.Content {
+		isHeader := len(row.Content) != 0
+		for _, cell := range row.Content {
+			if cell.Type != "tableHeader" {
+				isHeader = false
+			}
+		}
+
+		var tableRow *domain.TableRow
+		if isHeader {
+			tableRow = table.AddHeaderRow()
+		} else {
+			tableRow = table.AddRow()
+		}
+		for _, cell := range row.Content {
+			readTableCell(tableRow.AddCell(), cell)
+		}
+	}
+}
+
+// readMedia reads the external images of a media node. Files in Jira media
+// cannot be represented.
+func readMedia(domainDoc *domain.Document, jn *jira.ADFNode) {
+	for _, media := range jn.Content {
+		if media.Type == "media" && media.AttrString("type") == "external" {
+			domainDoc.AddImage(media.AttrString("url"), media.AttrString("alt"))
+		}
+	}
+}
+
+func readBlocks(domainDoc *domain.Document, jns []*jira.ADFNode) {
+	for _, jn := range jns {
+		switch jn.Type {
+		case "paragraph":
+			tc := domainDoc.AddParagraph()
+			readTextContainer(tc, jn.Content)
+		case "heading":
+			domainDoc.AddHeading(readHeadingLevel(jn), jn.PlainText())
+		case "bulletList":
+			readList(domainDoc.AddUnorderedList(), jn)
+		case "orderedList":
+			readList(domainDoc.AddOrderedList(), jn)
+		case "taskList":
+			readTaskList(domainDoc.AddTaskList(), jn)
+		case "codeBlock":
+			domainDoc.AddCodeBlock(jn.AttrString("language"), jn.PlainText())
+		case "blockquote":
+			readBlocks(&domainDoc.AddBlockQuote().Document, jn.Content)
+		case "panel":
+			panel := domainDoc.AddPanel(readPanelType(jn))
+			readBlocks(&panel.Document, jn.Content)
+		case "expand", "nestedExpand":
+			expand := domainDoc.AddExpand(jn.AttrString("title"))
+			readBlocks(&expand.Document, jn.Content)
+		case "table":
+			readTable(domainDoc, jn)
+		case "rule":
+			domainDoc.AddRule()
+		case "mediaSingle", "mediaGroup":
+			readMedia(domainDoc, jn)
+		default:
+			// nodes that cannot be represented keep their block content
+			readBlocks(domainDoc, jn.Content)
+		}
+	}
+}
+
+// readDocument maps a parsed Atlassian Document Format document to a domain
+// document.
+func readDocument(jd *jira.ADFNode) (*domain.Document, error) {
+	if jd == nil {
+		return nil, nil
+	}
+	if jd.Type != "doc" {
+		return nil, fmt.Errorf("Unexpected document node type '%s'", jd.Type)
+	}
+
+	domainDoc := &domain.Document{}
+	readBlocks(domainDoc, jd.Content)
+	if len(domainDoc.Nodes) == 0 {
+		return nil, nil
+	}
+	return domainDoc, nil
+}
@@ -1,7 +1,16 @@
 package tracker
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/glestaris/issuez/domain"
 	"github.com/glestaris/issuez/jira"
@@ -10,22 +19,135 @@ import (
 // APP layer
 //  Test using integration tests
 
+// defaultReleaseIssueType is the Jira issue type releases are imported as,
+// unless they are imported as versions.
+const defaultReleaseIssueType = "Task"
+
+func init() {
+	RegisterBackend(Backend{
+		Type:        "jira",
+		Description: "Jira Cloud, through its REST API",
+		Settings: []Setting{
+			{
+				Key:         "apiHost",
+				Type:        SettingTypeString,
+				Required:    true,
+				Description: "The URL of the Jira site",
+			},
+			{
+				Key:         "apiUsername",
+				Type:        SettingTypeString,
+				Required:    true,
+				Description: "The username of the Jira account",
+			},
+			{
+				Key:         "apiToken",
+				Type:        SettingTypeString,
+				Required:    true,
+				Description: "The API token of the Jira account",
+			},
+			{
+				Key:         "projectKey",
+				Type:        SettingTypeString,
+				Description: "The project issues are imported in",
+			},
+			{
+				Key:  "maxAttachmentSize",
+				Type: SettingTypeInt,
+				Description: "The maximum size, in bytes, of attachments " +
+					"(0 for no limit)",
+			},
+			{
+				Key:  "linkIssueKeys",
+				Type: SettingTypeBool,
+				Description: "Turn the issue keys of the project in " +
+					"descriptions into links",
+			},
+			{
+				Key:         "releaseIssueType",
+				Type:        SettingTypeString,
+				Description: "The issue type releases are imported as",
+				Default:     defaultReleaseIssueType,
+			},
+			{
+				Key:  "releaseVersions",
+				Type: SettingTypeBool,
+				Description: "Import releases as versions of the project " +
+					"instead of issues",
+			},
+			{
+				Key:  "requestTimeout",
+				Type: SettingTypeDuration,
+				Description: "The timeout of each request " +
+					"(0 for no timeout)",
+			},
+		},
+		New: func(config Config) (TrackerService, error) {
+			return newJiraTrackerService(
+				config.String("apiHost"),
+				config.String("apiUsername"),
+				config.String("apiToken"),
+				config.String("projectKey"),
+				config.Int("maxAttachmentSize"),
+				config.Bool("linkIssueKeys"),
+				config.String("releaseIssueType"),
+				config.Bool("releaseVersions"),
+				config.Duration("requestTimeout"),
+			), nil
+		},
+	})
+}
+
 type jiraTrackerService struct {
-	jiraClient *jira.Client
-	projectKey string
+	jiraClient        *jira.Client
+	apiHost           string
+	projectKey        string
+	maxAttachmentSize int64
+	linkIssueKeys     bool
+	releaseIssueType  string
+	releaseVersions   bool
 }
 
 func newJiraTrackerService(
 	apiHost string, apiUsername string, apiToken string, projectKey string,
+	maxAttachmentSize int64, linkIssueKeys bool, releaseIssueType string,
+	releaseVersions bool, requestTimeout time.Duration,
 ) TrackerService {
-	jiraClient := jira.NewJiraClient(apiHost, apiUsername, apiToken, nil)
+	// the timeout of the requests, unlike the context, is per request
+	httpClient := &http.Client{Timeout: requestTimeout}
+	jiraClient := jira.NewJiraClient(
+		apiHost, apiUsername, apiToken, httpClient,
+	)
 	return &jiraTrackerService{
-		jiraClient: jiraClient,
-		projectKey: projectKey,
+		jiraClient:        jiraClient,
+		apiHost:           apiHost,
+		projectKey:        projectKey,
+		maxAttachmentSize: maxAttachmentSize,
+		linkIssueKeys:     linkIssueKeys,
+		releaseIssueType:  releaseIssueType,
+		releaseVersions:   releaseVersions,
 	}
 }
 
-func (j *jiraTrackerService) ImportIssues(domainIssues []*domain.Issue) error {
+func (j *jiraTrackerService) ImportIssues(
+	ctx context.Context, domainIssues []*domain.Issue,
+) error {
+	if err := j.resolveMentions(ctx, domainIssues); err != nil {
+		return err
+	}
+	if j.linkIssueKeys && j.projectKey != "" {
+		j.addIssueKeyLinks(domainIssues)
+	}
+	var fixVersions map[*domain.Issue]string
+	if j.releaseVersions {
+		var err error
+		fixVersions, err = j.createReleaseVersions(ctx, domainIssues)
+		if err != nil {
+			return err
+		}
+		domainIssues = withoutReleases(domainIssues)
+	}
+
 	jiraIssues := make([]*jira.Issue, len(domainIssues))
 	for i, domainIssue := range domainIssues {
 		jiraIssue := &jira.Issue{}
@@ -40,6 +162,9 @@ func (j *jiraTrackerService) ImportIssues(domainIssues []*domain.Issue) error {
 			jiraIssue.Type = jira.IssueTypeBug
 		case domain.IssueTypeChore:
 			jiraIssue.Type = jira.IssueTypeTask
+		case domain.IssueTypeRelease:
+			jiraIssue.Type = jira.IssueTypeTask
+			jiraIssue.TypeName = j.releaseIssueType
 		}
 
 		// map title
@@ -65,14 +190,17 @@ func (j *jiraTrackerService) ImportIssues(domainIssues []*domain.Issue) error {
 			jiraIssue.Labels = append(jiraIssue.Labels, domainLabel.Label)
 		}
 
-		jiraIssues[i] = jiraIssue
-	}
+		// map release
+		if fixVersion, ok := fixVersions[domainIssue]; ok {
+			jiraIssue.FixVersions = []string{fixVersion}
+		}
 
-	resp, err := j.jiraClient.ImportIssues(jiraIssues)
-	if err != nil {
-		return err
+		jiraIssues[i] = jiraIssue
 	}
 
+	// the issues created before a failure, e.g. a cancellation, keep their
+	// keys, so that they can be reported
+	resp, importErr := j.jiraClient.ImportIssues(ctx, jiraIssues)
 	for i, entry := range resp {
 		if entry.Err != nil {
 			log.Printf("Failed to import issue '%s': %s",
@@ -82,10 +210,357 @@ func (j *jiraTrackerService) ImportIssues(domainIssues []*domain.Issue) error {
 		}
 		domainIssues[i].ID = entry.NewIssueKey
 	}
+	if importErr != nil {
+		return importErr
+	}
+
+	for _, domainIssue := range domainIssues {
+		if domainIssue.ID == "" {
+			continue
+		}
+
+		if err := j.attachImages(ctx, domainIssue); err != nil {
+			if isCancelled(err) {
+				return err
+			}
+			log.Printf("Failed to embed images in issue '%s': %s",
+				domainIssue.Title,
+				err)
+		}
+		if err := j.attachFiles(ctx, domainIssue); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createReleaseVersions creates a version of the project for each release,
+// unless the project already has a version with its name, and sets the ID
+// of the release to the ID of the version. It returns the versions the
+// issues are fixed in: the first release after each issue. Releases whose
+// version cannot be created are reported and the issues before them are
+// not fixed in any version. Only the error of a cancelled request is
+// returned.
+func (j *jiraTrackerService) createReleaseVersions(
+	ctx context.Context, domainIssues []*domain.Issue,
+) (map[*domain.Issue]string, error) {
+	versionIDs := map[string]string{}
+	versions, err := j.jiraClient.ProjectVersions(ctx, j.projectKey)
+	if isCancelled(err) {
+		return nil, err
+	} else if err != nil {
+		log.Printf("Failed to find the versions of project '%s': %s",
+			j.projectKey,
+			err)
+	}
+	for _, version := range versions {
+		versionIDs[version.Name] = version.ID
+	}
+
+	fixVersions := map[*domain.Issue]string{}
+	releaseIssues := []*domain.Issue{}
+	for _, domainIssue := range domainIssues {
+		if domainIssue.Type != domain.IssueTypeRelease {
+			releaseIssues = append(releaseIssues, domainIssue)
+			continue
+		}
+
+		versionID, ok := versionIDs[domainIssue.Title]
+		if !ok {
+			version, err := j.jiraClient.CreateVersion(
+				ctx, j.projectKey, domainIssue.Title,
+			)
+			if isCancelled(err) {
+				return nil, err
+			} else if err != nil {
+				log.Printf("Failed to create version for release '%s': %s",
+					domainIssue.Title,
+					err)
+				releaseIssues = []*domain.Issue{}
+				continue
+			}
+			versionID = version.ID
+			versionIDs[domainIssue.Title] = versionID
+		}
+		domainIssue.ID = versionID
+		for _, releaseIssue := range releaseIssues {
+			fixVersions[releaseIssue] = domainIssue.Title
+		}
+		releaseIssues = []*domain.Issue{}
+	}
+	return fixVersions, nil
+}
 
+// withoutReleases returns the issues which are not releases.
+func withoutReleases(domainIssues []*domain.Issue) []*domain.Issue {
+	issues := []*domain.Issue{}
+	for _, domainIssue := range domainIssues {
+		if domainIssue.Type != domain.IssueTypeRelease {
+			issues = append(issues, domainIssue)
+		}
+	}
+	return issues
+}
+
+// resolveMentions sets the account IDs of the users mentioned in the
+// descriptions of the issues. Each user is looked up once, however many
+// times they are mentioned. The users who are not found are reported and
+// their mentions are kept as text. Only the error of a cancelled request is
+// returned.
+func (j *jiraTrackerService) resolveMentions(
+	ctx context.Context, domainIssues []*domain.Issue,
+) error {
+	accountIDs := map[string]string{}
+	for _, domainIssue := range domainIssues {
+		if domainIssue.Description == nil {
+			continue
+		}
+
+		for _, mention := range domainIssue.Description.Mentions() {
+			accountID, ok := accountIDs[mention.Text]
+			if !ok {
+				var err error
+				accountID, err = j.findUser(ctx, mention.Text)
+				if isCancelled(err) {
+					return err
+				} else if err != nil {
+					log.Printf("Failed to find user '%s' mentioned in issue "+
+						"'%s': %s",
+						mention.Text,
+						domainIssue.Title,
+						err)
+				}
+				accountIDs[mention.Text] = accountID
+			}
+			mention.AccountID = accountID
+		}
+	}
 	return nil
 }
 
-func (j *jiraTrackerService) TestConnection() error {
-	return j.jiraClient.Test()
+// addIssueKeyLinks turns the keys of the issues of the project written in
+// the descriptions of the issues into smart links to the issues. Keys in
+// code or in links are kept as they are.
+func (j *jiraTrackerService) addIssueKeyLinks(domainIssues []*domain.Issue) {
+	keyRe := regexp.MustCompile(
+		`\b` + regexp.QuoteMeta(j.projectKey) + `-\d+\b`,
+	)
+	for _, domainIssue := range domainIssues {
+		if domainIssue.Description == nil {
+			continue
+		}
+
+		for _, tc := range domainIssue.Description.TextContainers() {
+			*tc = linkIssueKeys(*tc, keyRe, j.issueURL)
+		}
+	}
+}
+
+func (j *jiraTrackerService) issueURL(issueKey string) string {
+	return strings.TrimRight(j.apiHost, "/") + "/browse/" + issueKey
+}
+
+// findUser returns the account ID of the user with the email address or
+// name. The user search matches the start of names too, so a user with the
+// exact email address or name is preferred over the only user found.
+func (j *jiraTrackerService) findUser(
+	ctx context.Context, user string,
+) (string, error) {
+	users, err := j.jiraClient.SearchUsers(ctx, user)
+	if err != nil {
+		return "", err
+	}
+
+	for _, jiraUser := range users {
+		if strings.EqualFold(jiraUser.EmailAddress, user) ||
+			strings.EqualFold(jiraUser.DisplayName, user) {
+			return jiraUser.AccountID, nil
+		}
+	}
+	switch len(users) {
+	case 0:
+		return "", errors.New("No such user")
+	case 1:
+		return users[0].AccountID, nil
+	default:
+		return "", fmt.Errorf("Found %d users", len(users))
+	}
+}
+
+func (j *jiraTrackerService) attachFile(
+	ctx context.Context, issueKey string, path string,
+) (*jira.Attachment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open file: %s", err)
+	}
+	defer file.Close()
+
+	if j.maxAttachmentSize > 0 {
+		info, err := file.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read file size: %s", err)
+		}
+		if info.Size() > j.maxAttachmentSize {
+			return nil, fmt.Errorf(
+				"File is larger than the maximum attachment size (%d > %d)",
+				info.Size(), j.maxAttachmentSize,
+			)
+		}
+	}
+
+	return j.jiraClient.AddAttachment(
+		ctx, issueKey, filepath.Base(path), file,
+	)
+}
+
+// attachFiles uploads the attachments of a created issue. The attachments
+// which fail to upload are reported one by one, and only the error of a
+// cancelled upload is returned.
+func (j *jiraTrackerService) attachFiles(
+	ctx context.Context, domainIssue *domain.Issue,
+) error {
+	for _, attachment := range domainIssue.Attachments {
+		_, err := j.attachFile(ctx, domainIssue.ID, attachment.Path)
+		if isCancelled(err) {
+			return err
+		} else if err != nil {
+			log.Printf("Failed to attach file '%s' to issue '%s': %s",
+				attachment.Path,
+				domainIssue.Title,
+				err)
+		}
+	}
+	return nil
+}
+
+// attachImages uploads the local images of the description of a created
+// issue as attachments and embeds the attachments in its description. The
+// images which fail to upload are reported and keep their paths, and the
+// attachments whose media cannot be found are reported and linked. A
+// cancelled request stops the embedding.
+func (j *jiraTrackerService) attachImages(
+	ctx context.Context, domainIssue *domain.Issue,
+) error {
+	if domainIssue.Description == nil {
+		return nil
+	}
+
+	// images are uploaded once, however many times they are embedded
+	uploaded := map[string]*jira.Attachment{}
+	mediaIDs := map[string]string{}
+	for _, image := range domainIssue.Description.Images() {
+		if !image.IsLocal() {
+			continue
+		}
+		if _, ok := uploaded[image.Source]; ok {
+			continue
+		}
+
+		attachment, err := j.attachFile(ctx, domainIssue.ID, image.Source)
+		if isCancelled(err) {
+			return err
+		} else if err != nil {
+			log.Printf("Failed to attach image '%s' to issue '%s': %s",
+				image.Source,
+				domainIssue.Title,
+				err)
+			uploaded[image.Source] = nil
+			continue
+		}
+		uploaded[image.Source] = attachment
+
+		mediaID, err := j.jiraClient.AttachmentMediaID(ctx, attachment.ID)
+		if isCancelled(err) {
+			return err
+		} else if err != nil {
+			log.Printf("Failed to embed image '%s' in issue '%s', "+
+				"linking it instead: %s",
+				image.Source,
+				domainIssue.Title,
+				err)
+			continue
+		}
+		mediaIDs[image.Source] = mediaID
+	}
+
+	isUpdated := false
+	for _, image := range domainIssue.Description.Images() {
+		if attachment := uploaded[image.Source]; attachment != nil {
+			image.AttachmentURL = attachment.ContentURL
+			image.MediaID = mediaIDs[image.Source]
+			isUpdated = true
+		}
+	}
+	if !isUpdated {
+		return nil
+	}
+
+	jiraDescriptionDoc, err := mapDocument(domainIssue.Description)
+	if err != nil {
+		return err
+	}
+	return j.jiraClient.UpdateIssueDescription(
+		ctx, domainIssue.ID, jiraDescriptionDoc,
+	)
+}
+
+func (j *jiraTrackerService) ExportIssues(
+	ctx context.Context, jql string,
+) ([]*domain.Issue, error) {
+	jiraIssues, err := j.jiraClient.SearchIssues(ctx, jql)
+	if err != nil {
+		return nil, err
+	}
+
+	domainIssues := make([]*domain.Issue, len(jiraIssues))
+	for i, jiraIssue := range jiraIssues {
+		domainIssue := &domain.Issue{}
+
+		// map key
+		domainIssue.ID = jiraIssue.Key
+
+		// map issue type
+		domainIssue.Type = domain.IssueTypeStory
+		switch jiraIssue.Type {
+		case jira.IssueTypeBug:
+			domainIssue.Type = domain.IssueTypeBug
+		case jira.IssueTypeTask:
+			domainIssue.Type = domain.IssueTypeChore
+		}
+
+		// map title
+		domainIssue.Title = jiraIssue.Summary
+
+		// map description
+		domainDescriptionDoc, err := readDocument(jiraIssue.Description)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"Failed to read description for issue '%s': %s",
+				jiraIssue.Key, err,
+			)
+		}
+		domainIssue.Description = domainDescriptionDoc
+
+		// map epic
+		if jiraIssue.EpicKey != "" {
+			domainIssue.Epic = &domain.Epic{ID: jiraIssue.EpicKey}
+		}
+
+		// map labels
+		for _, jiraLabel := range jiraIssue.Labels {
+			domainIssue.Labels = append(domainIssue.Labels, domain.Label{
+				Label: jiraLabel,
+			})
+		}
+
+		domainIssues[i] = domainIssue
+	}
+
+	return domainIssues, nil
+}
+
+func (j *jiraTrackerService) TestConnection(ctx context.Context) error {
+	return j.jiraClient.Test(ctx)
 }
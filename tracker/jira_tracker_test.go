@@ -0,0 +1,734 @@
+package tracker_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/tracker"
+	"github.com/stretchr/testify/require"
+)
+
+func newFakeJiraTrackerService(
+	t *testing.T, handler http.HandlerFunc,
+) (tracker.TrackerService, func()) {
+	server := httptest.NewServer(handler)
+	trackerService, err := tracker.NewTrackerService(domain.Tracker{
+		Type: "jira",
+		Config: map[string]string{
+			"apiHost":     server.URL,
+			"apiUsername": "user",
+			"apiToken":    "token",
+			"projectKey":  "TEST",
+		},
+	})
+	require.NoError(t, err)
+
+	return trackerService, server.Close
+}
+
+// jiraConfig adds the required settings of Jira trackers to the config.
+func jiraConfig(config map[string]string) map[string]string {
+	config["apiHost"] = "https://jira.example.com/"
+	config["apiUsername"] = "user"
+	config["apiToken"] = "token"
+	return config
+}
+
+func searchHandler(t *testing.T, issues string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/rest/api/3/search", r.URL.Path)
+
+		var respIssues []json.RawMessage
+		require.NoError(t, json.Unmarshal([]byte(issues), &respIssues))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"startAt":    0,
+			"maxResults": 50,
+			"total":      len(respIssues),
+			"issues":     respIssues,
+		})
+	}
+}
+
+func TestJiraTrackerExportIssues(t *testing.T) {
+	trackerService, closeServer := newFakeJiraTrackerService(
+		t, searchHandler(t, `[
+  {
+    "key": "TEST-1",
+    "fields": {
+      "issuetype": { "name": "Bug" },
+      "summary": "A bug",
+      "parent": { "key": "TEST-100" },
+      "labels": ["label-1", "label-2"],
+      "description": null
+    }
+  },
+  {
+    "key": "TEST-2",
+    "fields": {
+      "issuetype": { "name": "Task" },
+      "summary": "A task",
+      "labels": []
+    }
+  },
+  {
+    "key": "TEST-3",
+    "fields": {
+      "issuetype": { "name": "Story" },
+      "summary": "A story",
+      "labels": []
+    }
+  }
+]`),
+	)
+	defer closeServer()
+
+	issues, err := trackerService.ExportIssues(
+		context.Background(), "project = TEST",
+	)
+	require.NoError(t, err)
+	require.Equal(t, []*domain.Issue{
+		{
+			ID:    "TEST-1",
+			Type:  domain.IssueTypeBug,
+			Title: "A bug",
+			Epic:  &domain.Epic{ID: "TEST-100"},
+			Labels: []domain.Label{
+				{Label: "label-1"},
+				{Label: "label-2"},
+			},
+		},
+		{
+			ID:    "TEST-2",
+			Type:  domain.IssueTypeChore,
+			Title: "A task",
+		},
+		{
+			ID:    "TEST-3",
+			Type:  domain.IssueTypeStory,
+			Title: "A story",
+		},
+	}, issues)
+}
+
+func TestJiraTrackerExportIssuesDescription(t *testing.T) {
+	trackerService, closeServer := newFakeJiraTrackerService(
+		t, searchHandler(t, `[
+  {
+    "key": "TEST-1",
+    "fields": {
+      "issuetype": { "name": "Bug" },
+      "summary": "A bug",
+      "description": {
+        "version": 1,
+        "type": "doc",
+        "content": [
+          {
+            "type": "heading",
+            "attrs": { "level": 2 },
+            "content": [{ "type": "text", "text": "Steps" }]
+          },
+          {
+            "type": "paragraph",
+            "content": [
+              { "type": "text", "text": "Hello " },
+              { "type": "text", "text": "world", "marks": [{ "type": "strong" }] },
+              { "type": "text", "text": " and " },
+              {
+                "type": "text",
+                "text": "links",
+                "marks": [
+                  { "type": "em" },
+                  { "type": "link", "attrs": { "href": "https://google.com" } }
+                ]
+              }
+            ]
+          },
+          {
+            "type": "orderedList",
+            "content": [
+              {
+                "type": "listItem",
+                "content": [
+                  {
+                    "type": "paragraph",
+                    "content": [
+                      { "type": "text", "text": "Item", "marks": [{ "type": "code" }] }
+                    ]
+                  }
+                ]
+              }
+            ]
+          },
+          {
+            "type": "panel",
+            "attrs": { "panelType": "info" },
+            "content": [
+              {
+                "type": "paragraph",
+                "content": [{ "type": "text", "text": "In a panel" }]
+              }
+            ]
+          },
+          {
+            "type": "codeBlock",
+            "attrs": { "language": "python" },
+            "content": [{ "type": "text", "text": "x = 12\n" }]
+          }
+        ]
+      }
+    }
+  }
+]`),
+	)
+	defer closeServer()
+
+	issues, err := trackerService.ExportIssues(
+		context.Background(), "project = TEST",
+	)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Equal(t, &domain.Document{
+		Nodes: []domain.DocumentNode{
+			{
+				Type: domain.DocumentNodeTypeHeading,
+				HeadingData: &domain.HeadingData{
+					Level: domain.HeadingLevel2,
+					Text:  "Steps",
+				},
+			},
+			{
+				Type: domain.DocumentNodeTypeParagraph,
+				ParagraphData: &domain.ParagraphData{
+					Content: domain.TextContainer{
+						Elements: []domain.TextElement{
+							{Text: "Hello "},
+							{
+								Text: "world",
+								Mode: domain.TextMode{Bold: true},
+							},
+							{Text: " and "},
+							{
+								Text:    "links",
+								Mode:    domain.TextMode{Italics: true},
+								LinkURL: "https://google.com",
+							},
+						},
+					},
+				},
+			},
+			{
+				Type: domain.DocumentNodeTypeList,
+				ListData: &domain.ListData{
+					IsOrdered: true,
+					Items: []domain.ListItem{
+						{
+							TextContainer: domain.TextContainer{
+								Elements: []domain.TextElement{
+									{
+										Text: "Item",
+										Mode: domain.TextMode{Code: true},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				Type: domain.DocumentNodeTypePanel,
+				PanelData: &domain.PanelData{
+					PanelType: domain.PanelTypeInfo,
+					Document: domain.Document{
+						Nodes: []domain.DocumentNode{
+							{
+								Type: domain.DocumentNodeTypeParagraph,
+								ParagraphData: &domain.ParagraphData{
+									Content: domain.TextContainer{
+										Elements: []domain.TextElement{
+											{Text: "In a panel"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				Type: domain.DocumentNodeTypeCodeBlock,
+				CodeBlockData: &domain.CodeBlockData{
+					Language: "python",
+					Code:     "x = 12\n",
+				},
+			},
+		},
+	}, issues[0].Description)
+}
+
+func TestJiraTrackerImportIssuesImages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "issuez")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	imagePath := filepath.Join(dir, "bug.png")
+	require.NoError(t, ioutil.WriteFile(imagePath, []byte("PNG"), 0644))
+	otherImagePath := filepath.Join(dir, "log.png")
+	require.NoError(t, ioutil.WriteFile(otherImagePath, []byte("PNG"), 0644))
+
+	attachmentCount := 0
+	var updatedDescription string
+	trackerService, closeServer := newFakeJiraTrackerService(
+		t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/rest/api/3/issue/bulk":
+				w.WriteHeader(201)
+				w.Write([]byte(`{"issues":[{"key":"TEST-1"}],"errors":[]}`))
+			case "/rest/api/3/issue/TEST-1/attachments":
+				attachmentCount++
+				fmt.Fprintf(w, `[{
+  "id": "%d",
+  "content": "https://e.com/attachment/content/%d"
+}]`, 9+attachmentCount, 9+attachmentCount)
+			case "/rest/api/3/attachment/content/10":
+				w.Header().Set(
+					"Location",
+					"https://api.media.atlassian.com/file/"+
+						"0a1b2c3d-1111-2222-3333-444455556666/binary",
+				)
+				w.WriteHeader(303)
+			case "/rest/api/3/attachment/content/11":
+				w.WriteHeader(404)
+			case "/rest/api/3/issue/TEST-1":
+				require.Equal(t, "PUT", r.Method)
+				body, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				updatedDescription = string(body)
+				w.WriteHeader(204)
+			default:
+				t.Fatalf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+		},
+	)
+	defer closeServer()
+
+	description := &domain.Document{}
+	description.AddImage(imagePath, "Screenshot")
+	description.AddImage(imagePath, "Again")
+	description.AddImage(otherImagePath, "Log")
+	description.AddImage("https://e.com/remote.png", "")
+	issue := &domain.Issue{
+		Type:        domain.IssueTypeBug,
+		Title:       "A bug",
+		Description: description,
+	}
+	require.NoError(t, trackerService.ImportIssues(
+		context.Background(), []*domain.Issue{issue},
+	))
+
+	require.Equal(t, "TEST-1", issue.ID)
+	require.Equal(t, 2, attachmentCount)
+	for _, altText := range []string{"Screenshot", "Again"} {
+		require.Contains(
+			t, updatedDescription,
+			`{"type":"media","attrs":{"alt":"`+altText+`","collection":"",`+
+				`"id":"0a1b2c3d-1111-2222-3333-444455556666","type":"file"}}`,
+		)
+	}
+	require.Contains(
+		t, updatedDescription,
+		`"text":"Log","marks":[{"type":"link",`+
+			`"attrs":{"href":"https://e.com/attachment/content/11"}}]`,
+	)
+	require.NotContains(t, updatedDescription, `"url":"https://e.com/attach`)
+	require.Contains(t, updatedDescription, `"url":"https://e.com/remote.png"`)
+}
+
+func TestJiraTrackerImportIssuesAttachments(t *testing.T) {
+	dir, err := ioutil.TempDir("", "issuez")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	logPath := filepath.Join(dir, "crash.log")
+	require.NoError(t, ioutil.WriteFile(logPath, []byte("panic"), 0644))
+	harPath := filepath.Join(dir, "trace.har")
+	require.NoError(t, ioutil.WriteFile(harPath, []byte("too large"), 0644))
+
+	var attachedFiles []string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/rest/api/3/issue/bulk":
+				w.WriteHeader(201)
+				w.Write([]byte(`{"issues":[{"key":"TEST-1"}],"errors":[]}`))
+			case "/rest/api/3/issue/TEST-1/attachments":
+				_, header, err := r.FormFile("file")
+				require.NoError(t, err)
+				attachedFiles = append(attachedFiles, header.Filename)
+				w.Write([]byte(`[{"id": "10", "filename": "crash.log"}]`))
+			default:
+				t.Fatalf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+		},
+	))
+	defer server.Close()
+	trackerService, err := tracker.NewTrackerService(domain.Tracker{
+		Type: "jira",
+		Config: map[string]string{
+			"apiHost":           server.URL,
+			"apiUsername":       "user",
+			"apiToken":          "token",
+			"projectKey":        "TEST",
+			"maxAttachmentSize": "5",
+		},
+	})
+	require.NoError(t, err)
+
+	issue := &domain.Issue{
+		Type:  domain.IssueTypeBug,
+		Title: "A bug",
+		Attachments: []domain.Attachment{
+			{Path: logPath},
+			{Path: filepath.Join(dir, "missing.log")},
+			{Path: harPath},
+		},
+	}
+	require.NoError(t, trackerService.ImportIssues(
+		context.Background(), []*domain.Issue{issue},
+	))
+
+	require.Equal(t, "TEST-1", issue.ID)
+	require.Equal(t, []string{"crash.log"}, attachedFiles)
+}
+
+func TestJiraTrackerImportIssuesCanceled(t *testing.T) {
+	dir, err := ioutil.TempDir("", "issuez")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	logPath := filepath.Join(dir, "crash.log")
+	require.NoError(t, ioutil.WriteFile(logPath, []byte("log"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	attachmentCount := 0
+	trackerService, closeServer := newFakeJiraTrackerService(
+		t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/rest/api/3/issue/bulk":
+				w.WriteHeader(201)
+				w.Write([]byte(
+					`{"issues":[{"key":"TEST-1"},{"key":"TEST-2"}],"errors":[]}`,
+				))
+			case "/rest/api/3/issue/TEST-1/attachments":
+				// cancelled while the first file is uploaded
+				attachmentCount++
+				_, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				cancel()
+				<-r.Context().Done()
+			default:
+				t.Fatalf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+		},
+	)
+	defer closeServer()
+
+	issues := []*domain.Issue{
+		{
+			Type:        domain.IssueTypeBug,
+			Title:       "A bug",
+			Attachments: []domain.Attachment{{Path: logPath}},
+		},
+		{
+			Type:        domain.IssueTypeBug,
+			Title:       "Another bug",
+			Attachments: []domain.Attachment{{Path: logPath}},
+		},
+	}
+	err = trackerService.ImportIssues(ctx, issues)
+	require.True(t, errors.Is(err, context.Canceled))
+	require.Regexp(
+		t, `^Failed to perform request: Post "[^"]+": context canceled$`,
+		err.Error(),
+	)
+
+	require.Equal(t, "TEST-1", issues[0].ID)
+	require.Equal(t, "TEST-2", issues[1].ID)
+	require.Equal(t, 1, attachmentCount)
+}
+
+func TestJiraTrackerInvalidRequestTimeout(t *testing.T) {
+	_, err := tracker.NewTrackerService(domain.Tracker{
+		Type:   "jira",
+		Config: jiraConfig(map[string]string{"requestTimeout": "soon"}),
+	})
+	require.EqualError(
+		t, err,
+		"Invalid setting 'requestTimeout' of tracker 'jira': "+
+			"'soon' is not a duration, such as 30s",
+	)
+}
+
+func TestJiraTrackerInvalidMaxAttachmentSize(t *testing.T) {
+	_, err := tracker.NewTrackerService(domain.Tracker{
+		Type:   "jira",
+		Config: jiraConfig(map[string]string{"maxAttachmentSize": "10MB"}),
+	})
+	require.EqualError(
+		t, err,
+		"Invalid setting 'maxAttachmentSize' of tracker 'jira': "+
+			"'10MB' is not a non-negative integer",
+	)
+}
+
+func TestJiraTrackerImportIssuesMentions(t *testing.T) {
+	searches := map[string]int{}
+	var importedIssues string
+	trackerService, closeServer := newFakeJiraTrackerService(
+		t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/rest/api/3/user/search":
+				query := r.URL.Query().Get("query")
+				searches[query]++
+				switch query {
+				case "alice@example.com":
+					w.Write([]byte(`[
+  {"accountId": "1", "displayName": "Alice", "emailAddress": ""}
+]`))
+				case "Bob":
+					w.Write([]byte(`[
+  {"accountId": "2", "displayName": "Bobby"},
+  {"accountId": "3", "displayName": "Bob"}
+]`))
+				default:
+					w.Write([]byte(`[]`))
+				}
+			case "/rest/api/3/issue/bulk":
+				body, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				importedIssues = string(body)
+				w.WriteHeader(201)
+				w.Write([]byte(
+					`{"issues":[{"key":"TEST-1"},{"key":"TEST-2"}],"errors":[]}`,
+				))
+			default:
+				t.Fatalf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+		},
+	)
+	defer closeServer()
+
+	description := &domain.Document{}
+	p := description.AddParagraph()
+	p.AddMention("alice@example.com", "")
+	p.AddMention("Bob", "")
+	p.AddMention("Carol", "")
+	otherDescription := &domain.Document{}
+	otherDescription.AddUnorderedList().AddItem().AddMention(
+		"alice@example.com", "",
+	)
+	issues := []*domain.Issue{
+		{Title: "A story", Description: description},
+		{Title: "Another story", Description: otherDescription},
+	}
+	require.NoError(
+		t, trackerService.ImportIssues(context.Background(), issues),
+	)
+
+	require.Equal(t, map[string]int{
+		"alice@example.com": 1,
+		"Bob":               1,
+		"Carol":             1,
+	}, searches)
+	require.Equal(t, "1", description.Nodes[0].Content.Elements[0].AccountID)
+	require.Equal(t, "3", description.Nodes[0].Content.Elements[1].AccountID)
+	require.Equal(t, "", description.Nodes[0].Content.Elements[2].AccountID)
+	require.Contains(
+		t, importedIssues,
+		`{"type":"mention","attrs":{"id":"1","text":"@alice@example.com"}}`,
+	)
+	require.Contains(t, importedIssues, `{"type":"text","text":"@Carol"}`)
+}
+
+func TestJiraTrackerImportIssuesIssueKeys(t *testing.T) {
+	var importedIssues string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/rest/api/3/issue/bulk", r.URL.Path)
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			importedIssues = string(body)
+			w.WriteHeader(201)
+			w.Write([]byte(`{"issues":[{"key":"TEST-2"}],"errors":[]}`))
+		},
+	))
+	defer server.Close()
+	trackerService, err := tracker.NewTrackerService(domain.Tracker{
+		Type: "jira",
+		Config: map[string]string{
+			"apiHost":       server.URL + "/",
+			"apiUsername":   "user",
+			"apiToken":      "token",
+			"projectKey":    "TEST",
+			"linkIssueKeys": "true",
+		},
+	})
+	require.NoError(t, err)
+
+	description := &domain.Document{}
+	description.AddParagraph().AddText("Follows TEST-1", domain.TextMode{})
+	issue := &domain.Issue{Title: "A story", Description: description}
+	require.NoError(t, trackerService.ImportIssues(
+		context.Background(), []*domain.Issue{issue},
+	))
+
+	require.Contains(
+		t, importedIssues,
+		`{"type":"inlineCard","attrs":{"url":"`+server.URL+`/browse/TEST-1"}}`,
+	)
+}
+
+func TestJiraTrackerInvalidLinkIssueKeys(t *testing.T) {
+	_, err := tracker.NewTrackerService(domain.Tracker{
+		Type:   "jira",
+		Config: jiraConfig(map[string]string{"linkIssueKeys": "yes please"}),
+	})
+	require.EqualError(
+		t, err,
+		"Invalid setting 'linkIssueKeys' of tracker 'jira': "+
+			"'yes please' is not true or false",
+	)
+}
+
+func TestJiraTrackerImportIssuesReleaseType(t *testing.T) {
+	var importedIssues string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/rest/api/3/issue/bulk", r.URL.Path)
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			importedIssues = string(body)
+			w.WriteHeader(201)
+			w.Write([]byte(`{"issues":[{"key":"TEST-1"}],"errors":[]}`))
+		},
+	))
+	defer server.Close()
+	trackerService, err := tracker.NewTrackerService(domain.Tracker{
+		Type: "jira",
+		Config: map[string]string{
+			"apiHost":          server.URL + "/",
+			"apiUsername":      "user",
+			"apiToken":         "token",
+			"projectKey":       "TEST",
+			"releaseIssueType": "Release",
+		},
+	})
+	require.NoError(t, err)
+
+	issue := &domain.Issue{Type: domain.IssueTypeRelease, Title: "Alpha"}
+	require.NoError(t, trackerService.ImportIssues(
+		context.Background(), []*domain.Issue{issue},
+	))
+	require.Contains(t, importedIssues, `"issuetype":{"name":"Release"}`)
+	require.Equal(t, "TEST-1", issue.ID)
+}
+
+func TestJiraTrackerImportIssuesReleaseVersions(t *testing.T) {
+	var importedIssues string
+	createdVersions := []string{}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/rest/api/3/project/TEST/versions":
+				w.Write([]byte(`[{"id":"10000","name":"Alpha"}]`))
+			case "/rest/api/3/version":
+				var version struct {
+					Name string `json:"name"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&version))
+				createdVersions = append(createdVersions, version.Name)
+				w.WriteHeader(201)
+				w.Write([]byte(`{"id":"10001","name":"Beta"}`))
+			case "/rest/api/3/issue/bulk":
+				body, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				importedIssues = string(body)
+				w.WriteHeader(201)
+				w.Write([]byte(`{"issues":[` +
+					`{"key":"TEST-1"},{"key":"TEST-2"},{"key":"TEST-3"}` +
+					`],"errors":[]}`))
+			default:
+				t.Fatalf("Unexpected request %s", r.URL.Path)
+			}
+		},
+	))
+	defer server.Close()
+	trackerService, err := tracker.NewTrackerService(domain.Tracker{
+		Type: "jira",
+		Config: map[string]string{
+			"apiHost":         server.URL + "/",
+			"apiUsername":     "user",
+			"apiToken":        "token",
+			"projectKey":      "TEST",
+			"releaseVersions": "true",
+		},
+	})
+	require.NoError(t, err)
+
+	issues := []*domain.Issue{
+		{Type: domain.IssueTypeStory, Title: "First"},
+		{Type: domain.IssueTypeRelease, Title: "Alpha"},
+		{Type: domain.IssueTypeBug, Title: "Second"},
+		{Type: domain.IssueTypeRelease, Title: "Beta"},
+		{Type: domain.IssueTypeChore, Title: "Third"},
+	}
+	require.NoError(
+		t, trackerService.ImportIssues(context.Background(), issues),
+	)
+
+	require.Equal(t, []string{"Beta"}, createdVersions)
+	var reqBody struct {
+		IssueUpdates []struct {
+			Fields struct {
+				Summary     string `json:"summary"`
+				FixVersions []struct {
+					Name string `json:"name"`
+				} `json:"fixVersions"`
+			} `json:"fields"`
+		} `json:"issueUpdates"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(importedIssues), &reqBody))
+	require.Len(t, reqBody.IssueUpdates, 3)
+	require.Equal(t, "First", reqBody.IssueUpdates[0].Fields.Summary)
+	require.Equal(
+		t, "Alpha", reqBody.IssueUpdates[0].Fields.FixVersions[0].Name,
+	)
+	require.Equal(
+		t, "Beta", reqBody.IssueUpdates[1].Fields.FixVersions[0].Name,
+	)
+	require.Empty(t, reqBody.IssueUpdates[2].Fields.FixVersions)
+
+	require.Equal(t, "TEST-1", issues[0].ID)
+	require.Equal(t, "10000", issues[1].ID)
+	require.Equal(t, "TEST-2", issues[2].ID)
+	require.Equal(t, "10001", issues[3].ID)
+	require.Equal(t, "TEST-3", issues[4].ID)
+}
+
+func TestJiraTrackerInvalidReleaseVersions(t *testing.T) {
+	_, err := tracker.NewTrackerService(domain.Tracker{
+		Type:   "jira",
+		Config: jiraConfig(map[string]string{"releaseVersions": "sometimes"}),
+	})
+	require.EqualError(
+		t, err,
+		"Invalid setting 'releaseVersions' of tracker 'jira': "+
+			"'sometimes' is not true or false",
+	)
+}
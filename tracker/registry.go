@@ -0,0 +1,197 @@
+package tracker
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SettingType is the type of the value of a setting of a tracker backend.
+type SettingType string
+
+// The types of settings. Integers and durations cannot be negative.
+const (
+	SettingTypeString   SettingType = "string"
+	SettingTypeInt      SettingType = "int"
+	SettingTypeBool     SettingType = "bool"
+	SettingTypeDuration SettingType = "duration"
+)
+
+// Setting is a key of the config of a tracker backend.
+type Setting struct {
+	Key         string
+	Type        SettingType
+	Required    bool
+	Description string
+
+	// Default is the value of the setting when it is not set or empty
+	Default string
+}
+
+// Backend is a kind of tracker, which tracker services are made for by its
+// factory, once their configs are validated against its settings.
+type Backend struct {
+	Type        string
+	Description string
+	Settings    []Setting
+	New         func(config Config) (TrackerService, error)
+}
+
+// Config is the config of a tracker, validated against the settings of its
+// backend. The getters return the values of the settings, or their zero
+// values when they are not set and have no default.
+type Config struct {
+	values map[string]interface{}
+}
+
+func (c Config) String(key string) string {
+	value, _ := c.values[key].(string)
+	return value
+}
+
+func (c Config) Int(key string) int64 {
+	value, _ := c.values[key].(int64)
+	return value
+}
+
+func (c Config) Bool(key string) bool {
+	value, _ := c.values[key].(bool)
+	return value
+}
+
+func (c Config) Duration(key string) time.Duration {
+	value, _ := c.values[key].(time.Duration)
+	return value
+}
+
+var (
+	backendsLock sync.RWMutex
+	backends     = map[string]Backend{}
+)
+
+// RegisterBackend makes the tracker services of the backend available to
+// NewTrackerService. It panics when a backend of the same type is already
+// registered or a default of its settings is invalid, like
+// database/sql.Register does.
+func RegisterBackend(backend Backend) {
+	backendsLock.Lock()
+	defer backendsLock.Unlock()
+
+	if backend.Type == "" || backend.New == nil {
+		panic("tracker: RegisterBackend with no type or factory")
+	}
+	if _, ok := backends[backend.Type]; ok {
+		panic("tracker: RegisterBackend called twice for " + backend.Type)
+	}
+	for _, setting := range backend.Settings {
+		if setting.Default == "" {
+			continue
+		}
+		if _, err := parseSetting(setting, setting.Default); err != nil {
+			panic(fmt.Sprintf(
+				"tracker: invalid default of %s setting %s: %s",
+				backend.Type, setting.Key, err,
+			))
+		}
+	}
+	backends[backend.Type] = backend
+}
+
+// Backends returns the registered backends, sorted by type.
+func Backends() []Backend {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+
+	list := make([]Backend, 0, len(backends))
+	for _, backend := range backends {
+		list = append(list, backend)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Type < list[j].Type
+	})
+	return list
+}
+
+// LookupBackend returns the registered backend of the type.
+func LookupBackend(trackerType string) (Backend, bool) {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+
+	backend, ok := backends[trackerType]
+	return backend, ok
+}
+
+// validateConfig checks that the config only has settings of the backend,
+// that its required settings are set and that the values have the types of
+// the settings. Empty values are the same as settings which are not set.
+func (b Backend) validateConfig(config map[string]string) (Config, error) {
+	settings := map[string]bool{}
+	for _, setting := range b.Settings {
+		settings[setting.Key] = true
+	}
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !settings[key] {
+			return Config{}, fmt.Errorf(
+				"Unknown setting '%s' of tracker '%s'", key, b.Type,
+			)
+		}
+	}
+
+	values := map[string]interface{}{}
+	for _, setting := range b.Settings {
+		value := config[setting.Key]
+		if value == "" {
+			if setting.Required {
+				return Config{}, fmt.Errorf(
+					"Missing setting '%s' of tracker '%s'", setting.Key, b.Type,
+				)
+			}
+			value = setting.Default
+		}
+		if value == "" {
+			continue
+		}
+
+		parsed, err := parseSetting(setting, value)
+		if err != nil {
+			return Config{}, fmt.Errorf(
+				"Invalid setting '%s' of tracker '%s': %s",
+				setting.Key, b.Type, err,
+			)
+		}
+		values[setting.Key] = parsed
+	}
+
+	return Config{values: values}, nil
+}
+
+func parseSetting(setting Setting, value string) (interface{}, error) {
+	switch setting.Type {
+	case SettingTypeInt:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || i < 0 {
+			return nil, fmt.Errorf("'%s' is not a non-negative integer", value)
+		}
+		return i, nil
+	case SettingTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not true or false", value)
+		}
+		return b, nil
+	case SettingTypeDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("'%s' is not a duration, such as 30s", value)
+		}
+		return d, nil
+	}
+	return value, nil
+}
@@ -0,0 +1,114 @@
+package tracker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glestaris/issuez/domain"
+	"github.com/glestaris/issuez/tracker"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTrackerService struct {
+	config tracker.Config
+}
+
+func (f *fakeTrackerService) ImportIssues(
+	ctx context.Context, issues []*domain.Issue,
+) error {
+	return nil
+}
+
+func (f *fakeTrackerService) ExportIssues(
+	ctx context.Context, query string,
+) ([]*domain.Issue, error) {
+	return nil, nil
+}
+
+func (f *fakeTrackerService) TestConnection(ctx context.Context) error {
+	return nil
+}
+
+var fakeBackend = tracker.Backend{
+	Type:        "fake",
+	Description: "A tracker of the tests",
+	Settings: []tracker.Setting{
+		{Key: "host", Type: tracker.SettingTypeString, Required: true},
+		{Key: "pageSize", Type: tracker.SettingTypeInt, Default: "50"},
+		{Key: "verbose", Type: tracker.SettingTypeBool},
+		{Key: "timeout", Type: tracker.SettingTypeDuration},
+	},
+	New: func(config tracker.Config) (tracker.TrackerService, error) {
+		return &fakeTrackerService{config: config}, nil
+	},
+}
+
+func init() {
+	tracker.RegisterBackend(fakeBackend)
+}
+
+func TestNewTrackerServiceConfig(t *testing.T) {
+	trackerService, err := tracker.NewTrackerService(domain.Tracker{
+		Type: "fake",
+		Config: map[string]string{
+			"host":    "localhost",
+			"verbose": "true",
+			"timeout": "30s",
+		},
+	})
+	require.NoError(t, err)
+
+	config := trackerService.(*fakeTrackerService).config
+	require.Equal(t, "localhost", config.String("host"))
+	require.Equal(t, int64(50), config.Int("pageSize"))
+	require.True(t, config.Bool("verbose"))
+	require.Equal(t, 30*time.Second, config.Duration("timeout"))
+}
+
+func TestNewTrackerServiceUnknownType(t *testing.T) {
+	_, err := tracker.NewTrackerService(domain.Tracker{Type: "trello"})
+	require.EqualError(t, err, "Unknown tracker type 'trello'")
+}
+
+func TestNewTrackerServiceUnknownSetting(t *testing.T) {
+	_, err := tracker.NewTrackerService(domain.Tracker{
+		Type:   "fake",
+		Config: map[string]string{"host": "localhost", "hots": "localhost"},
+	})
+	require.EqualError(t, err, "Unknown setting 'hots' of tracker 'fake'")
+}
+
+func TestNewTrackerServiceMissingSetting(t *testing.T) {
+	_, err := tracker.NewTrackerService(domain.Tracker{
+		Type:   "fake",
+		Config: map[string]string{"host": "", "verbose": "false"},
+	})
+	require.EqualError(t, err, "Missing setting 'host' of tracker 'fake'")
+}
+
+func TestNewTrackerServiceInvalidSetting(t *testing.T) {
+	_, err := tracker.NewTrackerService(domain.Tracker{
+		Type:   "fake",
+		Config: map[string]string{"host": "localhost", "pageSize": "-1"},
+	})
+	require.EqualError(
+		t, err,
+		"Invalid setting 'pageSize' of tracker 'fake': "+
+			"'-1' is not a non-negative integer",
+	)
+}
+
+func TestBackends(t *testing.T) {
+	types := []string{}
+	for _, backend := range tracker.Backends() {
+		types = append(types, backend.Type)
+	}
+	require.Equal(t, []string{"fake", "github", "jira"}, types)
+}
+
+func TestRegisterBackendTwice(t *testing.T) {
+	require.Panics(t, func() {
+		tracker.RegisterBackend(fakeBackend)
+	})
+}
@@ -1,6 +1,8 @@
 package tracker
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/glestaris/issuez/domain"
@@ -9,20 +11,36 @@ import (
 // APP layer
 //  Test using integration tests
 
+// TrackerService imports issues to and exports issues from a tracker. When
+// the context is cancelled, the requests in flight are cancelled and
+// ImportIssues returns the error of the cancelled request, which is
+// context.Canceled as errors.Is reports, with the IDs of the issues it
+// created set.
 type TrackerService interface {
-	ImportIssues(issues []*domain.Issue) error
-	TestConnection() error
+	ImportIssues(ctx context.Context, issues []*domain.Issue) error
+	ExportIssues(ctx context.Context, query string) ([]*domain.Issue, error)
+	TestConnection(ctx context.Context) error
 }
 
+// NewTrackerService returns a tracker service of the registered backend of
+// the type of the tracker, once its config is validated against the settings
+// of the backend.
 func NewTrackerService(tracker domain.Tracker) (TrackerService, error) {
-	if tracker.Type == "jira" {
-		return newJiraTrackerService(
-			tracker.Config["apiHost"],
-			tracker.Config["apiUsername"],
-			tracker.Config["apiToken"],
-			tracker.Config["projectKey"],
-		), nil
+	backend, ok := LookupBackend(tracker.Type)
+	if !ok {
+		return nil, fmt.Errorf("Unknown tracker type '%s'", tracker.Type)
 	}
 
-	return nil, fmt.Errorf("Unknown tracker type '%s'", tracker.Type)
+	config, err := backend.validateConfig(tracker.Config)
+	if err != nil {
+		return nil, err
+	}
+	return backend.New(config)
+}
+
+// isCancelled checks whether the error is the one of a request whose context
+// was cancelled. Requests which time out fail on their own and do not stop
+// the import.
+func isCancelled(err error) bool {
+	return errors.Is(err, context.Canceled)
 }